@@ -1,38 +1,62 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/linkbreakers-com/grpc-mcp-gateway/internal/annotations"
 
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
+// Plugin parameters such as use_proto_names=true apply the matching Tool
+// annotation to every tool in the file.
+var (
+	flags           flag.FlagSet
+	emitUnpopulated = flags.Bool("emit_unpopulated", false, "emit zero-valued fields in tool results")
+	useProtoNames   = flags.Bool("use_proto_names", false, "use proto field names in tool arguments and results")
+	useEnumNumbers  = flags.Bool("use_enum_numbers", false, "encode enum values as numbers in tool results")
+	discardUnknown  = flags.Bool("discard_unknown", false, "ignore unknown fields in tool arguments")
+)
+
 func main() {
-	opts := protogen.Options{}
+	opts := protogen.Options{ParamFunc: flags.Set}
 	opts.Run(func(plugin *protogen.Plugin) error {
 		plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
 		for _, file := range plugin.Files {
 			if !file.Generate {
 				continue
 			}
-			generateFile(plugin, file)
+			if err := generateFile(plugin, file); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
 }
 
-func generateFile(plugin *protogen.Plugin, file *protogen.File) {
+func generateFile(plugin *protogen.Plugin, file *protogen.File) error {
 	var services []*protogen.Service
+	needsHandlers := false
+	needsIO, needsTime := false, false
 	for _, service := range file.Services {
 		if hasAnnotatedMethods(service) {
 			services = append(services, service)
+			needsHandlers = true
+			usesIO, usesTime := toolImports(service)
+			needsIO = needsIO || usesIO
+			needsTime = needsTime || usesTime
+		} else if hasPrompts(service) {
+			services = append(services, service)
 		}
 	}
 	if len(services) == 0 {
-		return
+		return nil
 	}
 
 	filename := file.GeneratedFilenamePrefix + "_mcp.pb.go"
@@ -45,31 +69,129 @@ func generateFile(plugin *protogen.Plugin, file *protogen.File) {
 	g.P()
 
 	g.P("import (")
-	g.P("\t\"context\"")
-	g.P("\t\"fmt\"")
-	g.P()
+	if needsHandlers {
+		g.P("\t\"context\"")
+		if needsIO {
+			g.P("\t\"io\"")
+		}
+		if needsTime {
+			g.P("\t\"time\"")
+		}
+		g.P()
+	}
 	g.P("\t\"github.com/linkbreakers-com/grpc-mcp-gateway/runtime\"")
 	g.P(")")
 	g.P()
 
 	for _, service := range services {
-		generateService(g, service)
+		if err := generateService(g, service); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func hasAnnotatedMethods(service *protogen.Service) bool {
 	for _, method := range service.Methods {
-		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
-			continue
+		if _, ok := annotations.ToolFromMethod(method.Desc); ok && isSupportedTool(method) {
+			return true
 		}
-		if _, ok := annotations.ToolFromMethod(method.Desc); ok {
+		if _, ok := annotations.ResourceFromMethod(method.Desc); ok && isUnary(method) {
 			return true
 		}
 	}
 	return false
 }
 
-func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
+// toolImports reports whether the tools of service need the io and time
+// packages.
+func toolImports(service *protogen.Service) (needsIO, needsTime bool) {
+	for _, method := range service.Methods {
+		tool, ok := annotations.ToolFromMethod(method.Desc)
+		if !ok || !isSupportedTool(method) {
+			continue
+		}
+		if tool.Timeout != "" {
+			needsTime = true
+		}
+		if isUnary(method) {
+			continue
+		}
+		if method.Desc.IsStreamingClient() {
+			needsIO = true
+		} else if progress, _, _ := progressFields(method.Output); progress != nil {
+			needsIO = true
+		} else if tool.StreamMaxDuration != "" {
+			needsTime = true
+		}
+	}
+	return needsIO, needsTime
+}
+
+func isUnary(method *protogen.Method) bool {
+	return !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer()
+}
+
+// isSupportedTool reports whether method can be exposed as a tool: unary,
+// server-streaming and client-streaming RPCs. Bidirectional streams are not.
+func isSupportedTool(method *protogen.Method) bool {
+	return !method.Desc.IsStreamingClient() || !method.Desc.IsStreamingServer()
+}
+
+// collectsStream reports whether the tool for method returns every message
+// of a server stream rather than a single response.
+func collectsStream(method *protogen.Method) bool {
+	if !method.Desc.IsStreamingServer() || method.Desc.IsStreamingClient() {
+		return false
+	}
+	progress, _, _ := progressFields(method.Output)
+	return progress == nil
+}
+
+// progressFields returns the fields of a streamed response message that
+// carry progress: a numeric "progress", and optionally a numeric "total" and
+// a string "message". progress is nil when the message has none.
+func progressFields(msg *protogen.Message) (progress, total, message *protogen.Field) {
+	for _, field := range msg.Fields {
+		if field.Desc.IsList() || field.Desc.IsMap() {
+			continue
+		}
+		switch field.Desc.Name() {
+		case "progress":
+			if isNumericKind(field.Desc.Kind()) {
+				progress = field
+			}
+		case "total":
+			if isNumericKind(field.Desc.Kind()) {
+				total = field
+			}
+		case "message":
+			if field.Desc.Kind() == protoreflect.StringKind {
+				message = field
+			}
+		}
+	}
+	return progress, total, message
+}
+
+func isNumericKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return true
+	}
+	return false
+}
+
+func hasPrompts(service *protogen.Service) bool {
+	opts, ok := annotations.ServiceFromService(service.Desc)
+	return ok && len(opts.Prompts) > 0
+}
+
+func generateService(g *protogen.GeneratedFile, service *protogen.Service) error {
 	serviceName := service.GoName
 	clientName := serviceName + "Client"
 
@@ -84,21 +206,54 @@ func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
 	g.P()
 
 	for _, method := range service.Methods {
-		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
-			continue
+		if tool, ok := annotations.ToolFromMethod(method.Desc); ok && isSupportedTool(method) {
+			if err := generateMethod(g, service, method, tool); err != nil {
+				return err
+			}
 		}
-		tool, ok := annotations.ToolFromMethod(method.Desc)
-		if !ok {
-			continue
+		if resource, ok := annotations.ResourceFromMethod(method.Desc); ok && isUnary(method) {
+			if err := generateResource(g, service, method, resource); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts, ok := annotations.ServiceFromService(service.Desc); ok {
+		for _, prompt := range opts.Prompts {
+			if err := generatePrompt(g, service, prompt); err != nil {
+				return err
+			}
 		}
-		generateMethod(g, service, method, tool)
 	}
 
 	g.P("}")
 	g.P()
+	return nil
 }
 
-func generateMethod(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method, tool annotations.ToolOptions) {
+func generateMethod(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method, tool annotations.ToolOptions) error {
+	var streamMaxDuration time.Duration
+	if tool.StreamMaxDuration != "" {
+		d, err := time.ParseDuration(tool.StreamMaxDuration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid stream_max_duration %q", method.Desc.FullName(), tool.StreamMaxDuration)
+		}
+		streamMaxDuration = d
+	}
+	var timeout time.Duration
+	if tool.Timeout != "" {
+		d, err := time.ParseDuration(tool.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid timeout %q", method.Desc.FullName(), tool.Timeout)
+		}
+		timeout = d
+	}
+
+	tool.EmitUnpopulated = tool.EmitUnpopulated || *emitUnpopulated
+	tool.UseProtoNames = tool.UseProtoNames || *useProtoNames
+	tool.UseEnumNumbers = tool.UseEnumNumbers || *useEnumNumbers
+	tool.DiscardUnknown = tool.DiscardUnknown || *discardUnknown
+
 	methodName := method.GoName
 
 	toolName := tool.Name
@@ -114,13 +269,35 @@ func generateMethod(g *protogen.GeneratedFile, service *protogen.Service, method
 		toolDescription = normalizeComment(method.Comments.Leading.String())
 	}
 
-	schema := buildMessageSchema(method.Input, make(map[string]bool))
+	inputOpts := schemaOptions{protoNames: tool.UseProtoNames}
+	outputOpts := schemaOptions{
+		output:          true,
+		protoNames:      tool.UseProtoNames,
+		enumNumbers:     tool.UseEnumNumbers,
+		emitUnpopulated: tool.EmitUnpopulated,
+	}
+	schema := buildRootSchema(method.Input, inputOpts)
+	if method.Desc.IsStreamingClient() {
+		schema = buildItemsSchema(schema, tool.MaxItems)
+	}
+	outputSchema := buildRootSchema(method.Output, outputOpts)
+	if collectsStream(method) {
+		outputSchema = buildResultSchema(map[string]any{
+			"type":  "array",
+			"items": outputSchema,
+		})
+	}
 
 	g.P("\tmux.RegisterTool(&runtime.ToolHandler{")
 	g.P("\t\tName: ", fmt.Sprintf("%q", toolName), ",")
 	g.P("\t\tTitle: ", fmt.Sprintf("%q", toolTitle), ",")
 	g.P("\t\tDescription: ", fmt.Sprintf("%q", toolDescription), ",")
 	emitSchemaField(g, "InputSchema", schema, "\t\t")
+	g.P("\t\tInputDescriptor: (&", g.QualifiedGoIdent(method.Input.GoIdent), "{}).ProtoReflect().Descriptor(),")
+	if method.Desc.IsStreamingClient() {
+		g.P("\t\tClientStreaming: true,")
+	}
+	emitSchemaField(g, "OutputSchema", outputSchema, "\t\t")
 	if tool.ReadOnly {
 		g.P("\t\tReadOnly: true,")
 	}
@@ -130,18 +307,385 @@ func generateMethod(g *protogen.GeneratedFile, service *protogen.Service, method
 	if tool.Destructive {
 		g.P("\t\tDestructive: true,")
 	}
+	if timeout > 0 {
+		g.P("\t\tTimeout: ", durationExpr(timeout), ",")
+	}
+	if tool.SkipValidation {
+		g.P("\t\tSkipValidation: true,")
+	}
+	if tool.CoerceArgs {
+		g.P("\t\tCoerceArgs: true,")
+	}
+	emitJSONOptions(g, tool)
+	if isUnary(method) {
+		emitUnaryHandler(g, method)
+	} else if method.Desc.IsStreamingClient() {
+		emitClientStreamHandler(g, method, tool.MaxItems)
+	} else if collectsStream(method) {
+		emitStreamHandler(g, method, tool.StreamMaxMessages, streamMaxDuration)
+	} else {
+		emitProgressHandler(g, method)
+	}
+	g.P("\t})")
+	return nil
+}
+
+func generateResource(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method, resource annotations.ResourceOptions) error {
+	if resource.URITemplate == "" {
+		return fmt.Errorf("%s: resource annotation requires uri_template", method.Desc.FullName())
+	}
+	for _, name := range uriTemplateVars(resource.URITemplate) {
+		field := findField(method.Input, name)
+		if field == nil {
+			return fmt.Errorf("%s: uri_template variable %q is not a field of %s", method.Desc.FullName(), name, method.Input.Desc.FullName())
+		}
+		if !isURIVariableField(field) {
+			return fmt.Errorf("%s: uri_template variable %q is bound to %s, but only singular string, integer and enum fields can be read from a URI", method.Desc.FullName(), name, describeFieldKind(field))
+		}
+	}
+
+	name := resource.Name
+	if name == "" {
+		name = service.GoName + "." + method.GoName
+	}
+	title := resource.Title
+	if title == "" {
+		title = method.GoName
+	}
+	description := resource.Description
+	if description == "" {
+		description = normalizeComment(method.Comments.Leading.String())
+	}
+
+	g.P("\tmux.RegisterResource(&runtime.ResourceHandler{")
+	g.P("\t\tURITemplate: ", fmt.Sprintf("%q", resource.URITemplate), ",")
+	g.P("\t\tName: ", fmt.Sprintf("%q", name), ",")
+	g.P("\t\tTitle: ", fmt.Sprintf("%q", title), ",")
+	g.P("\t\tDescription: ", fmt.Sprintf("%q", description), ",")
+	if resource.MIMEType != "" {
+		g.P("\t\tMIMEType: ", fmt.Sprintf("%q", resource.MIMEType), ",")
+	}
+	emitUnaryHandler(g, method)
+	g.P("\t})")
+
+	for _, name := range uriTemplateVars(resource.URITemplate) {
+		field := findField(method.Input, name)
+		if field.Desc.Kind() != protoreflect.EnumKind {
+			continue
+		}
+		ref := "runtime.ResourceCompletionRef(" + fmt.Sprintf("%q", resource.URITemplate) + ")"
+		emitStaticCompletion(g, ref, name, enumValueNames(field))
+	}
+	return nil
+}
+
+// emitStaticCompletion emits a completion source that offers a fixed list of
+// values for one argument.
+func emitStaticCompletion(g *protogen.GeneratedFile, ref, argument string, values []string) {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	g.P("\tmux.RegisterCompletion(", ref, ", ", fmt.Sprintf("%q", argument), ", runtime.StaticCompletion(", strings.Join(quoted, ", "), "))")
+}
+
+func generatePrompt(g *protogen.GeneratedFile, service *protogen.Service, prompt annotations.PromptOptions) error {
+	if prompt.Name == "" {
+		return fmt.Errorf("%s: prompt annotation requires a name", service.Desc.FullName())
+	}
+	declared := make(map[string]bool, len(prompt.Arguments))
+	for _, arg := range prompt.Arguments {
+		declared[arg.Name] = true
+	}
+	for _, msg := range prompt.Messages {
+		for _, name := range promptPlaceholders(msg.Text) {
+			if !declared[name] {
+				return fmt.Errorf("%s: prompt %q references undeclared argument %q", service.Desc.FullName(), prompt.Name, name)
+			}
+		}
+	}
+
+	g.P("\tmux.RegisterPrompt(&runtime.Prompt{")
+	g.P("\t\tName: ", fmt.Sprintf("%q", prompt.Name), ",")
+	if prompt.Title != "" {
+		g.P("\t\tTitle: ", fmt.Sprintf("%q", prompt.Title), ",")
+	}
+	if prompt.Description != "" {
+		g.P("\t\tDescription: ", fmt.Sprintf("%q", prompt.Description), ",")
+	}
+	if len(prompt.Arguments) > 0 {
+		g.P("\t\tArguments: []runtime.PromptArgument{")
+		for _, arg := range prompt.Arguments {
+			g.P("\t\t\t{Name: ", fmt.Sprintf("%q", arg.Name), ", Description: ", fmt.Sprintf("%q", arg.Description), ", Required: ", arg.Required, "},")
+		}
+		g.P("\t\t},")
+	}
+	g.P("\t\tMessages: []runtime.PromptMessage{")
+	for _, msg := range prompt.Messages {
+		role := msg.Role
+		if role == "" {
+			role = "user"
+		}
+		g.P("\t\t\t{Role: ", fmt.Sprintf("%q", role), ", Text: ", fmt.Sprintf("%q", msg.Text), "},")
+	}
+	g.P("\t\t},")
+	g.P("\t})")
+
+	for _, arg := range prompt.Arguments {
+		if len(arg.Values) > 0 {
+			ref := "runtime.PromptCompletionRef(" + fmt.Sprintf("%q", prompt.Name) + ")"
+			emitStaticCompletion(g, ref, arg.Name, arg.Values)
+		}
+	}
+	return nil
+}
+
+var promptPlaceholderExpr = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// promptPlaceholders returns the argument names referenced by a prompt text.
+func promptPlaceholders(text string) []string {
+	var names []string
+	for _, m := range promptPlaceholderExpr.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// emitJSONOptions emits the JSONOptions field for the protojson flags set on
+// tool, if any.
+func emitJSONOptions(g *protogen.GeneratedFile, tool annotations.ToolOptions) {
+	protoBool := g.QualifiedGoIdent(protogen.GoIdent{GoName: "Bool", GoImportPath: "google.golang.org/protobuf/proto"})
+	var fields []string
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"EmitUnpopulated", tool.EmitUnpopulated},
+		{"UseProtoNames", tool.UseProtoNames},
+		{"UseEnumNumbers", tool.UseEnumNumbers},
+		{"DiscardUnknown", tool.DiscardUnknown},
+	} {
+		if flag.set {
+			fields = append(fields, flag.name+": "+protoBool+"(true)")
+		}
+	}
+	if len(fields) > 0 {
+		g.P("\t\tJSONOptions: &runtime.ToolJSONOptions{", strings.Join(fields, ", "), "},")
+	}
+}
+
+// emitUnaryHandler emits a Handler field that decodes args into the request
+// message, invokes the unary RPC and encodes its response.
+func emitUnaryHandler(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("\t\tHandler: func(ctx context.Context, args map[string]any) (any, error) {")
 	g.P("\t\t\treq := &", g.QualifiedGoIdent(method.Input.GoIdent), "{}")
-	g.P("\t\t\tif err := runtime.DecodeArgs(args, req); err != nil {")
-	g.P("\t\t\t\treturn nil, fmt.Errorf(\"invalid arguments: %v\", err)")
+	g.P("\t\t\tif err := runtime.DecodeArgsContext(ctx, args, req); err != nil {")
+	g.P("\t\t\t\treturn nil, err")
 	g.P("\t\t\t}")
-	g.P("\t\t\tresp, err := client.", methodName, "(ctx, req)")
+	g.P("\t\t\tresp, err := client.", method.GoName, "(ctx, req, runtime.ResponseMetadata(ctx)...)")
 	g.P("\t\t\tif err != nil {")
 	g.P("\t\t\t\treturn nil, err")
 	g.P("\t\t\t}")
-	g.P("\t\t\treturn runtime.EncodeProto(resp)")
+	g.P("\t\t\treturn runtime.EncodeProtoContext(ctx, resp)")
 	g.P("\t\t},")
-	g.P("\t})")
+}
+
+// emitProgressHandler emits a Handler field for a server-streaming RPC whose
+// messages report progress. Each message is forwarded as a progress
+// notification and the last one becomes the tool result.
+func emitProgressHandler(g *protogen.GeneratedFile, method *protogen.Method) {
+	progress, total, message := progressFields(method.Output)
+	totalExpr := "0"
+	if total != nil {
+		totalExpr = "float64(msg.Get" + total.GoName + "())"
+	}
+	messageExpr := `""`
+	if message != nil {
+		messageExpr = "msg.Get" + message.GoName + "()"
+	}
+
+	g.P("\t\tHandler: func(ctx context.Context, args map[string]any) (any, error) {")
+	g.P("\t\t\treq := &", g.QualifiedGoIdent(method.Input.GoIdent), "{}")
+	g.P("\t\t\tif err := runtime.DecodeArgsContext(ctx, args, req); err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\tstream, err := client.", method.GoName, "(ctx, req, runtime.ResponseMetadata(ctx)...)")
+	g.P("\t\t\tif err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\treporter := runtime.ProgressFromContext(ctx)")
+	g.P("\t\t\tvar last *", g.QualifiedGoIdent(method.Output.GoIdent))
+	g.P("\t\t\tfor {")
+	g.P("\t\t\t\tmsg, err := stream.Recv()")
+	g.P("\t\t\t\tif err == io.EOF {")
+	g.P("\t\t\t\t\tbreak")
+	g.P("\t\t\t\t}")
+	g.P("\t\t\t\tif err != nil {")
+	g.P("\t\t\t\t\treturn nil, err")
+	g.P("\t\t\t\t}")
+	g.P("\t\t\t\treporter.Report(float64(msg.Get", progress.GoName, "()), ", totalExpr, ", ", messageExpr, ")")
+	g.P("\t\t\t\tlast = msg")
+	g.P("\t\t\t}")
+	g.P("\t\t\treturn runtime.EncodeProtoContext(ctx, last)")
+	g.P("\t\t},")
+}
+
+// emitStreamHandler emits a Handler field for a server-streaming RPC that
+// collects the streamed messages within the given limits.
+func emitStreamHandler(g *protogen.GeneratedFile, method *protogen.Method, maxMessages uint32, maxDuration time.Duration) {
+	var limits []string
+	if maxMessages > 0 {
+		limits = append(limits, fmt.Sprintf("MaxMessages: %d", maxMessages))
+	}
+	if maxDuration > 0 {
+		limits = append(limits, "MaxDuration: "+durationExpr(maxDuration))
+	}
+
+	g.P("\t\tHandler: func(ctx context.Context, args map[string]any) (any, error) {")
+	g.P("\t\t\treq := &", g.QualifiedGoIdent(method.Input.GoIdent), "{}")
+	g.P("\t\t\tif err := runtime.DecodeArgsContext(ctx, args, req); err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\tlimits := runtime.StreamLimits{", strings.Join(limits, ", "), "}")
+	g.P("\t\t\treturn runtime.CollectStream(ctx, limits, func(ctx context.Context) (runtime.StreamReceiver[*", g.QualifiedGoIdent(method.Output.GoIdent), "], error) {")
+	g.P("\t\t\t\treturn client.", method.GoName, "(ctx, req)")
+	g.P("\t\t\t})")
+	g.P("\t\t},")
+}
+
+// defaultMaxItems is the cap runtime.StreamItems applies when a tool sets no
+// max_items, runtime.DefaultStreamMaxItems.
+const defaultMaxItems = 1000
+
+// buildItemsSchema wraps the schema of a client-streaming request message
+// into an object whose "items" array holds one message per element, at most
+// maxItems of them, or defaultMaxItems when maxItems is zero.
+func buildItemsSchema(itemSchema map[string]any, maxItems uint32) map[string]any {
+	if maxItems == 0 {
+		maxItems = defaultMaxItems
+	}
+	items := map[string]any{
+		"type":     "array",
+		"items":    itemSchema,
+		"maxItems": int(maxItems),
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{"items": items},
+		"required":             []string{"items"},
+		"additionalProperties": false,
+	}
+}
+
+// buildResultSchema describes a non-object tool result, which the runtime
+// wraps as {"result": <value>} in structuredContent.
+func buildResultSchema(valueSchema map[string]any) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{"result": valueSchema},
+		"required":             []string{"result"},
+		"additionalProperties": false,
+	}
+}
+
+// emitClientStreamHandler emits a Handler field for a client-streaming RPC.
+// Every item is decoded before the stream is opened, then sent in order; the
+// final response becomes the tool result.
+func emitClientStreamHandler(g *protogen.GeneratedFile, method *protogen.Method, maxItems uint32) {
+	g.P("\t\tHandler: func(ctx context.Context, args map[string]any) (any, error) {")
+	g.P("\t\t\titems, err := runtime.StreamItems(args, ", maxItems, ")")
+	g.P("\t\t\tif err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\treqs := make([]*", g.QualifiedGoIdent(method.Input.GoIdent), ", len(items))")
+	g.P("\t\t\tfor i, item := range items {")
+	g.P("\t\t\t\treqs[i] = &", g.QualifiedGoIdent(method.Input.GoIdent), "{}")
+	g.P("\t\t\t\tif err := runtime.DecodeArgsContext(ctx, item, reqs[i]); err != nil {")
+	g.P("\t\t\t\t\treturn nil, runtime.ItemArgumentError(i, err)")
+	g.P("\t\t\t\t}")
+	g.P("\t\t\t}")
+	g.P("\t\t\tstream, err := client.", method.GoName, "(ctx, runtime.ResponseMetadata(ctx)...)")
+	g.P("\t\t\tif err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\tfor _, req := range reqs {")
+	g.P("\t\t\t\tif err := stream.Send(req); err != nil {")
+	g.P("\t\t\t\t\tif err == io.EOF {")
+	g.P("\t\t\t\t\t\tbreak // the server ended the stream; CloseAndRecv reports why")
+	g.P("\t\t\t\t\t}")
+	g.P("\t\t\t\t\treturn nil, err")
+	g.P("\t\t\t\t}")
+	g.P("\t\t\t}")
+	g.P("\t\t\tresp, err := stream.CloseAndRecv()")
+	g.P("\t\t\tif err != nil {")
+	g.P("\t\t\t\treturn nil, err")
+	g.P("\t\t\t}")
+	g.P("\t\t\treturn runtime.EncodeProtoContext(ctx, resp)")
+	g.P("\t\t},")
+}
+
+// durationExpr returns a Go expression for d using the time package.
+func durationExpr(d time.Duration) string {
+	switch {
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%d * time.Minute", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	case d%time.Millisecond == 0:
+		return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
+var uriTemplateExpr = regexp.MustCompile(`\{[+]?([A-Za-z0-9_.]+)\}`)
+
+// uriTemplateVars returns the variable names of a URI template.
+func uriTemplateVars(template string) []string {
+	var vars []string
+	for _, m := range uriTemplateExpr.FindAllStringSubmatch(template, -1) {
+		vars = append(vars, m[1])
+	}
+	return vars
+}
+
+// findField returns the field of msg with the given proto or JSON name.
+func findField(msg *protogen.Message, name string) *protogen.Field {
+	for _, field := range msg.Fields {
+		if string(field.Desc.Name()) == name || field.Desc.JSONName() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// isURIVariableField reports whether a URI template variable can be bound to
+// field. Variables are decoded as JSON strings, which protojson accepts only
+// for strings, integers and enum names.
+func isURIVariableField(field *protogen.Field) bool {
+	if field.Desc.IsList() || field.Desc.IsMap() {
+		return false
+	}
+	switch field.Desc.Kind() {
+	case protoreflect.StringKind, protoreflect.EnumKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+// describeFieldKind names the type of field for error messages, e.g.
+// "a repeated string field".
+func describeFieldKind(field *protogen.Field) string {
+	switch {
+	case field.Desc.IsMap():
+		return "a map field"
+	case field.Desc.IsList():
+		return "a repeated " + field.Desc.Kind().String() + " field"
+	}
+	return "a " + field.Desc.Kind().String() + " field"
 }
 
 func normalizeComment(comment string) string {
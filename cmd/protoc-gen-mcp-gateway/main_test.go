@@ -0,0 +1,353 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	gatewayv1 "github.com/linkbreakers-com/grpc-mcp-gateway/mcp/gateway/v1"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// taskFile returns a proto3 file declaring a TaskService with the given
+// methods and options, and the messages they use:
+//
+//	enum Status { STATUS_UNSPECIFIED = 0; STATUS_OPEN = 1; STATUS_DONE = 2; }
+//	message Task { string name = 1; Status status = 2; repeated string tags = 3; int64 count = 4; }
+//	message ImportResult { int32 imported = 1; }
+//	message Progress { int32 progress = 1; int32 total = 2; string message = 3; }
+func taskFile(opts *descriptorpb.ServiceOptions, methods ...*descriptorpb.MethodDescriptorProto) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("tasks/v1/tasks.proto"),
+		Package: proto.String("tasks.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/tasks/v1;tasksv1")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			enumType("Status", "STATUS_UNSPECIFIED", "STATUS_OPEN", "STATUS_DONE"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Task"), Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				typedField("status", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".tasks.v1.Status"),
+				repeated(scalarField("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING)),
+				scalarField("count", 4, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+			}},
+			{Name: proto.String("ImportResult"), Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("imported", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+			}},
+			{Name: proto.String("Progress"), Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("progress", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+				scalarField("total", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+				scalarField("message", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:    proto.String("TaskService"),
+			Method:  methods,
+			Options: opts,
+		}},
+	}
+}
+
+func enumType(name string, values ...string) *descriptorpb.EnumDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(name)}
+	for i, v := range values {
+		enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(v), Number: proto.Int32(int32(i))})
+	}
+	return enum
+}
+
+func scalarField(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return typedField(name, number, typ, "")
+}
+
+func typedField(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   typ.Enum(),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+	return field
+}
+
+func repeated(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return field
+}
+
+// method declares an RPC annotated with opts, if non-nil.
+func method(name, input, output string, opts *gatewayv1.MethodOptions) *descriptorpb.MethodDescriptorProto {
+	m := &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(".tasks.v1." + input),
+		OutputType: proto.String(".tasks.v1." + output),
+	}
+	if opts != nil {
+		m.Options = &descriptorpb.MethodOptions{}
+		setUnknownOption(m.Options, methodOptionNumber, opts)
+	}
+	return m
+}
+
+// serviceOptions returns service options annotated with opts.
+func serviceOptions(opts *gatewayv1.ServiceOptions) *descriptorpb.ServiceOptions {
+	o := &descriptorpb.ServiceOptions{}
+	setUnknownOption(o, serviceOptionNumber, opts)
+	return o
+}
+
+const (
+	methodOptionNumber  = 51234
+	serviceOptionNumber = 51235
+)
+
+// setUnknownOption stores ext in options as unknown fields, the way protoc
+// hands custom options to a plugin that does not link their definitions.
+func setUnknownOption(options proto.Message, number protowire.Number, ext proto.Message) {
+	b, err := proto.Marshal(ext)
+	if err != nil {
+		panic(err)
+	}
+	raw := protowire.AppendTag(nil, number, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, b)
+	options.ProtoReflect().SetUnknown(raw)
+}
+
+// generate runs the plugin on file and returns the generated source with
+// runs of whitespace collapsed to single spaces, so that assertions do not
+// depend on gofmt's alignment.
+func generate(t *testing.T, file *descriptorpb.FileDescriptorProto) (string, error) {
+	t.Helper()
+	plugin := newPlugin(t, file)
+	if err := generateFile(plugin, plugin.FilesByPath[file.GetName()]); err != nil {
+		return "", err
+	}
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatalf("generated code does not format: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 {
+		t.Fatalf("generated %d files, want 1", len(resp.File))
+	}
+	return strings.Join(strings.Fields(resp.File[0].GetContent()), " "), nil
+}
+
+func newPlugin(t *testing.T, file *descriptorpb.FileDescriptorProto) *protogen.Plugin {
+	t.Helper()
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("load %s: %v", file.GetName(), err)
+	}
+	return plugin
+}
+
+// assertContains fails t for each of want missing from src.
+func assertContains(t *testing.T, src string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(src, w) {
+			t.Errorf("generated code is missing %q:\n%s", w, src)
+		}
+	}
+}
+
+func TestGenerateResource(t *testing.T) {
+	read := &gatewayv1.MethodOptions{Resource: &gatewayv1.Resource{
+		UriTemplate: "tasks://{name}/{status}",
+		MimeType:    "application/json",
+	}}
+	src, err := generate(t, taskFile(nil, method("GetTask", "Task", "Task", read)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, src,
+		`mux.RegisterResource(&runtime.ResourceHandler{ URITemplate: "tasks://{name}/{status}", Name: "TaskService.GetTask", Title: "GetTask",`,
+		`MIMEType: "application/json",`,
+		`if err := runtime.DecodeArgsContext(ctx, args, req); err != nil {`,
+		`client.GetTask(ctx, req, runtime.ResponseMetadata(ctx)...)`,
+	)
+
+	for _, tc := range []struct {
+		template string
+		want     string
+	}{
+		{"tasks://{id}", `uri_template variable "id" is not a field of tasks.v1.Task`},
+		{"tasks://{tags}", `uri_template variable "tags" is bound to a repeated string field`},
+		{"tasks://{count}/{name}", ""},
+		{"", "resource annotation requires uri_template"},
+	} {
+		read := &gatewayv1.MethodOptions{Resource: &gatewayv1.Resource{UriTemplate: tc.template}}
+		_, err := generate(t, taskFile(nil, method("GetTask", "Task", "Task", read)))
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%q: %v", tc.template, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: got error %v, want %q", tc.template, err, tc.want)
+		}
+	}
+
+	file := taskFile(nil, method("GetTask", "Task", "Task", read))
+	file.MessageType[0].Field = append(file.MessageType[0].Field,
+		typedField("parent", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".tasks.v1.Task"))
+	read = &gatewayv1.MethodOptions{Resource: &gatewayv1.Resource{UriTemplate: "tasks://{parent}"}}
+	file.Service[0].Method[0] = method("GetTask", "Task", "Task", read)
+	if _, err := generate(t, file); err == nil || !strings.Contains(err.Error(), `"parent" is bound to a message field`) {
+		t.Errorf("message variable: got error %v", err)
+	}
+}
+
+func TestGeneratePrompts(t *testing.T) {
+	prompt := &gatewayv1.Prompt{
+		Name:        "triage",
+		Title:       "Triage",
+		Description: "Sort open tasks.",
+		Arguments: []*gatewayv1.PromptArgument{
+			{Name: "team", Description: "Owning team", Required: true},
+			{Name: "limit"},
+		},
+		Messages: []*gatewayv1.PromptMessage{
+			{Text: "Triage the tasks of {{ team }}, at most {{limit}}."},
+			{Role: "assistant", Text: "Fetching tasks."},
+		},
+	}
+	// A service with prompts and no annotated methods still gets a
+	// registration function.
+	src, err := generate(t, taskFile(serviceOptions(&gatewayv1.ServiceOptions{Prompts: []*gatewayv1.Prompt{prompt}})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, src,
+		`func RegisterTaskServiceMCPHandler(mux *runtime.MCPServeMux, client TaskServiceClient) {`,
+		`mux.RegisterPrompt(&runtime.Prompt{ Name: "triage", Title: "Triage", Description: "Sort open tasks.",`,
+		`{Name: "team", Description: "Owning team", Required: true}, {Name: "limit", Description: "", Required: false},`,
+		`{Role: "user", Text: "Triage the tasks of {{ team }}, at most {{limit}}."}, {Role: "assistant", Text: "Fetching tasks."},`,
+	)
+	if strings.Contains(src, `"context"`) {
+		t.Errorf("prompt-only file imports context:\n%s", src)
+	}
+
+	prompt.Messages = append(prompt.Messages, &gatewayv1.PromptMessage{Text: "Skip {{owner}}."})
+	_, err = generate(t, taskFile(serviceOptions(&gatewayv1.ServiceOptions{Prompts: []*gatewayv1.Prompt{prompt}})))
+	if err == nil || !strings.Contains(err.Error(), `prompt "triage" references undeclared argument "owner"`) {
+		t.Errorf("undeclared argument: got error %v", err)
+	}
+
+	_, err = generate(t, taskFile(serviceOptions(&gatewayv1.ServiceOptions{Prompts: []*gatewayv1.Prompt{{Title: "Nameless"}}})))
+	if err == nil || !strings.Contains(err.Error(), "prompt annotation requires a name") {
+		t.Errorf("nameless prompt: got error %v", err)
+	}
+}
+
+func TestGenerateCompletions(t *testing.T) {
+	read := &gatewayv1.MethodOptions{Resource: &gatewayv1.Resource{UriTemplate: "tasks://{name}/{status}"}}
+	opts := serviceOptions(&gatewayv1.ServiceOptions{Prompts: []*gatewayv1.Prompt{{
+		Name: "triage",
+		Arguments: []*gatewayv1.PromptArgument{
+			{Name: "team", Values: []string{"core", "infra"}},
+			{Name: "note"},
+		},
+		Messages: []*gatewayv1.PromptMessage{{Text: "Triage {{team}}: {{note}}"}},
+	}}})
+	src, err := generate(t, taskFile(opts, method("GetTask", "Task", "Task", read)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Enum variables offer the input schema's values, without the zero-value
+	// sentinel; string variables and free-form arguments get no completion.
+	assertContains(t, src,
+		`mux.RegisterCompletion(runtime.ResourceCompletionRef("tasks://{name}/{status}"), "status", runtime.StaticCompletion("STATUS_OPEN", "STATUS_DONE"))`,
+		`mux.RegisterCompletion(runtime.PromptCompletionRef("triage"), "team", runtime.StaticCompletion("core", "infra"))`,
+	)
+	if n := strings.Count(src, "mux.RegisterCompletion("); n != 2 {
+		t.Errorf("got %d completion registrations, want 2:\n%s", n, src)
+	}
+}
+
+// streaming marks m as a client- and/or server-streaming RPC.
+func streaming(m *descriptorpb.MethodDescriptorProto, client, server bool) *descriptorpb.MethodDescriptorProto {
+	m.ClientStreaming = proto.Bool(client)
+	m.ServerStreaming = proto.Bool(server)
+	return m
+}
+
+func TestGenerateServerStreamingTools(t *testing.T) {
+	list := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.list", StreamMaxMessages: 50, StreamMaxDuration: "30s"}}
+	watch := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.watch"}}
+	sync := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.sync"}}
+	src, err := generate(t, taskFile(nil,
+		streaming(method("ListTasks", "Task", "Task", list), false, true),
+		streaming(method("WatchTask", "Task", "Progress", watch), false, true),
+		streaming(method("SyncTasks", "Task", "Task", sync), true, true),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, src,
+		`import ( "context" "io" "time" "github.com/linkbreakers-com/grpc-mcp-gateway/runtime" )`,
+		// A plain stream is collected within its limits and wrapped as
+		// {"result": [...]}.
+		`limits := runtime.StreamLimits{MaxMessages: 50, MaxDuration: 30 * time.Second}`,
+		`return runtime.CollectStream(ctx, limits, func(ctx context.Context) (runtime.StreamReceiver[*Task], error) { return client.ListTasks(ctx, req) })`,
+		`OutputSchema: map[string]any{ "additionalProperties": false, "properties": map[string]any{ "result": map[string]any{ "items": map[string]any{`,
+		// A stream of progress messages reports each one and returns the last.
+		`reporter.Report(float64(msg.GetProgress()), float64(msg.GetTotal()), msg.GetMessage())`,
+		`return runtime.EncodeProtoContext(ctx, last)`,
+	)
+	if strings.Contains(src, `"tasks.sync"`) {
+		t.Errorf("bidirectional stream registered as a tool:\n%s", src)
+	}
+
+	list.Tool.StreamMaxDuration = "soon"
+	_, err = generate(t, taskFile(nil, streaming(method("ListTasks", "Task", "Task", list), false, true)))
+	if err == nil || !strings.Contains(err.Error(), `invalid stream_max_duration "soon"`) {
+		t.Errorf("invalid duration: got error %v", err)
+	}
+}
+
+func TestGenerateClientStreamingTool(t *testing.T) {
+	for _, tc := range []struct {
+		maxItems uint32
+		want     string
+	}{
+		{0, `"maxItems": 1000,`},
+		{500, `"maxItems": 500,`},
+	} {
+		imp := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.import", MaxItems: tc.maxItems}}
+		src, err := generate(t, taskFile(nil, streaming(method("ImportTasks", "Task", "ImportResult", imp), true, false)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertContains(t, src,
+			`import ( "context" "io" "github.com/linkbreakers-com/grpc-mcp-gateway/runtime" )`,
+			`InputSchema: map[string]any{ "additionalProperties": false, "properties": map[string]any{ "items": map[string]any{ "items": map[string]any{`,
+			tc.want,
+			`"required": []string{ "items", },`,
+			`ClientStreaming: true,`,
+			`items, err := runtime.StreamItems(args, `,
+			`return nil, runtime.ItemArgumentError(i, err)`,
+			`stream, err := client.ImportTasks(ctx, runtime.ResponseMetadata(ctx)...)`,
+			`resp, err := stream.CloseAndRecv()`,
+		)
+	}
+
+	unary := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.get"}}
+	src, err := generate(t, taskFile(nil, method("GetTask", "Task", "Task", unary)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(src, "ClientStreaming") {
+		t.Errorf("unary tool marked client-streaming:\n%s", src)
+	}
+}
@@ -86,17 +86,51 @@ func isRequired(field *protogen.Field) bool {
 	return false
 }
 
-func buildMessageSchema(msg *protogen.Message, seen map[string]bool) map[string]any {
+// schemaOptions selects which side of the wire a schema describes.
+type schemaOptions struct {
+	// output describes messages as the runtime encodes them: output-only
+	// fields are included, nothing is required (unpopulated fields are
+	// omitted), 64-bit integers are strings and open enums may hold
+	// numbers they do not declare.
+	output bool
+	// protoNames names properties after the proto fields instead of their
+	// lowerCamelCase JSON names.
+	protoNames bool
+	// enumNumbers describes output enums as their numbers.
+	enumNumbers bool
+	// emitUnpopulated lets unset output message fields be null, as
+	// protojson emits them.
+	emitUnpopulated bool
+}
+
+// propertyName returns the name field is encoded under.
+func (o schemaOptions) propertyName(field *protogen.Field) string {
+	if o.protoNames {
+		return string(field.Desc.Name())
+	}
+	return field.Desc.JSONName()
+}
+
+// buildRootSchema describes a request or response message. A Struct is
+// encoded as the bare JSON object it holds.
+func buildRootSchema(msg *protogen.Message, opts schemaOptions) map[string]any {
+	if msg.Desc.FullName() == "google.protobuf.Struct" {
+		return map[string]any{"type": "object", "additionalProperties": true}
+	}
+	return buildMessageSchema(msg, opts, make(map[string]bool))
+}
+
+func buildMessageSchema(msg *protogen.Message, opts schemaOptions, seen map[string]bool) map[string]any {
 	properties := map[string]any{}
 	var required []string
 
 	for _, field := range msg.Fields {
-		if isOutputOnly(field) {
+		if isOutputOnly(field) && !opts.output {
 			continue
 		}
 
-		jsonName := field.Desc.JSONName()
-		schema := buildFieldSchema(field, seen)
+		jsonName := opts.propertyName(field)
+		schema := buildFieldSchema(field, opts, seen)
 
 		desc := normalizeComment(field.Comments.Leading.String())
 		if desc != "" {
@@ -105,7 +139,7 @@ func buildMessageSchema(msg *protogen.Message, seen map[string]bool) map[string]
 
 		properties[jsonName] = schema
 
-		if isRequired(field) {
+		if isRequired(field) && !opts.output {
 			required = append(required, jsonName)
 		}
 	}
@@ -121,10 +155,10 @@ func buildMessageSchema(msg *protogen.Message, seen map[string]bool) map[string]
 	return result
 }
 
-func buildFieldSchema(field *protogen.Field, seen map[string]bool) map[string]any {
+func buildFieldSchema(field *protogen.Field, opts schemaOptions, seen map[string]bool) map[string]any {
 	if field.Desc.IsMap() {
 		valueField := field.Message.Fields[1]
-		valueSchema := buildFieldSchema(valueField, seen)
+		valueSchema := buildFieldSchema(valueField, opts, seen)
 		return map[string]any{
 			"type":                 "object",
 			"additionalProperties": valueSchema,
@@ -132,17 +166,23 @@ func buildFieldSchema(field *protogen.Field, seen map[string]bool) map[string]an
 	}
 
 	if field.Desc.IsList() {
-		elemSchema := buildScalarOrMessageSchema(field, seen)
+		elemSchema := buildScalarOrMessageSchema(field, opts, seen)
 		return map[string]any{
 			"type":  "array",
 			"items": elemSchema,
 		}
 	}
 
-	return buildScalarOrMessageSchema(field, seen)
+	schema := buildScalarOrMessageSchema(field, opts, seen)
+	if opts.output && opts.emitUnpopulated && field.Message != nil && field.Desc.ContainingOneof() == nil {
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []any{typ, "null"}
+		}
+	}
+	return schema
 }
 
-func buildScalarOrMessageSchema(field *protogen.Field, seen map[string]bool) map[string]any {
+func buildScalarOrMessageSchema(field *protogen.Field, opts schemaOptions, seen map[string]bool) map[string]any {
 	switch field.Desc.Kind() {
 	case protoreflect.BoolKind:
 		return map[string]any{"type": "boolean"}
@@ -153,25 +193,66 @@ func buildScalarOrMessageSchema(field *protogen.Field, seen map[string]bool) map
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
 		return map[string]any{"type": "integer", "format": "int32"}
 	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-		return map[string]any{"type": "integer", "format": "int64"}
+		return int64Schema(opts)
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		return map[string]any{"type": "integer", "format": "int32"}
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return map[string]any{"type": "integer", "format": "int64"}
+		return int64Schema(opts)
 	case protoreflect.FloatKind:
 		return map[string]any{"type": "number", "format": "float"}
 	case protoreflect.DoubleKind:
 		return map[string]any{"type": "number", "format": "double"}
 	case protoreflect.EnumKind:
-		return buildEnumSchema(field)
+		return buildEnumSchema(field, opts)
 	case protoreflect.MessageKind, protoreflect.GroupKind:
-		return buildNestedMessageSchema(field, seen)
+		return buildNestedMessageSchema(field, opts, seen)
 	default:
 		return map[string]any{}
 	}
 }
 
-func buildEnumSchema(field *protogen.Field) map[string]any {
+// int64Schema describes a 64-bit integer, which protojson encodes as a
+// string but accepts either way.
+func int64Schema(opts schemaOptions) map[string]any {
+	if opts.output {
+		return map[string]any{"type": "string", "format": "int64"}
+	}
+	return map[string]any{"type": "integer", "format": "int64"}
+}
+
+// buildEnumSchema describes an enum field. Input schemas list the values a
+// client may pick. An open (proto3) enum field can hold numbers the enum does
+// not declare, which protojson encodes as bare integers, so output schemas
+// only list the values of closed enums.
+func buildEnumSchema(field *protogen.Field, opts schemaOptions) map[string]any {
+	if opts.output && !field.Desc.Enum().IsClosed() {
+		if opts.enumNumbers {
+			return map[string]any{"type": "integer"}
+		}
+		return map[string]any{"type": []any{"string", "integer"}}
+	}
+	if opts.output && opts.enumNumbers {
+		values := field.Desc.Enum().Values()
+		numbers := make([]any, values.Len())
+		for i := range numbers {
+			numbers[i] = int(values.Get(i).Number())
+		}
+		return map[string]any{"type": "integer", "enum": numbers}
+	}
+	if opts.output {
+		values := field.Desc.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]any{"type": "string", "enum": names}
+	}
+	return map[string]any{"type": "string", "enum": enumValueNames(field)}
+}
+
+// enumValueNames returns the names of the enum values a client may pick,
+// leaving out the zero-value sentinel unless it is the only value.
+func enumValueNames(field *protogen.Field) []string {
 	enumDesc := field.Desc.Enum()
 	values := enumDesc.Values()
 	var enumVals []string
@@ -187,10 +268,10 @@ func buildEnumSchema(field *protogen.Field) map[string]any {
 			enumVals = append(enumVals, string(values.Get(i).Name()))
 		}
 	}
-	return map[string]any{"type": "string", "enum": enumVals}
+	return enumVals
 }
 
-func buildNestedMessageSchema(field *protogen.Field, seen map[string]bool) map[string]any {
+func buildNestedMessageSchema(field *protogen.Field, opts schemaOptions, seen map[string]bool) map[string]any {
 	fullName := string(field.Message.Desc.FullName())
 
 	switch protoreflect.FullName(fullName) {
@@ -213,11 +294,11 @@ func buildNestedMessageSchema(field *protogen.Field, seen map[string]bool) map[s
 	case "google.protobuf.Int32Value":
 		return map[string]any{"type": "integer", "format": "int32"}
 	case "google.protobuf.Int64Value":
-		return map[string]any{"type": "integer", "format": "int64"}
+		return int64Schema(opts)
 	case "google.protobuf.UInt32Value":
 		return map[string]any{"type": "integer", "format": "int32"}
 	case "google.protobuf.UInt64Value":
-		return map[string]any{"type": "integer", "format": "int64"}
+		return int64Schema(opts)
 	case "google.protobuf.FloatValue":
 		return map[string]any{"type": "number", "format": "float"}
 	case "google.protobuf.DoubleValue":
@@ -231,7 +312,7 @@ func buildNestedMessageSchema(field *protogen.Field, seen map[string]bool) map[s
 	}
 
 	seen[fullName] = true
-	schema := buildMessageSchema(field.Message, seen)
+	schema := buildMessageSchema(field.Message, opts, seen)
 	delete(seen, fullName)
 
 	return schema
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	gatewayv1 "github.com/linkbreakers-com/grpc-mcp-gateway/mcp/gateway/v1"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// withBehaviors annotates field with google.api.field_behavior values.
+func withBehaviors(field *descriptorpb.FieldDescriptorProto, behaviors ...int) *descriptorpb.FieldDescriptorProto {
+	var raw []byte
+	for _, b := range behaviors {
+		raw = protowire.AppendTag(raw, fieldBehaviorFieldNumber, protowire.VarintType)
+		raw = protowire.AppendVarint(raw, uint64(b))
+	}
+	field.Options = &descriptorpb.FieldOptions{}
+	field.Options.ProtoReflect().SetUnknown(raw)
+	return field
+}
+
+// taskMessage returns the Task message of file.
+func taskMessage(t *testing.T, file *descriptorpb.FileDescriptorProto) *protogen.Message {
+	t.Helper()
+	for _, msg := range newPlugin(t, file).FilesByPath[file.GetName()].Messages {
+		if msg.Desc.Name() == "Task" {
+			return msg
+		}
+	}
+	t.Fatal("no Task message")
+	return nil
+}
+
+func TestOutputSchema(t *testing.T) {
+	file := taskFile(nil)
+	task := file.MessageType[0]
+	task.Field[0] = withBehaviors(task.Field[0], fieldBehaviorRequired)
+	task.Field = append(task.Field, withBehaviors(scalarField("create_time", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING), fieldBehaviorOutputOnly))
+	msg := taskMessage(t, file)
+
+	input := buildRootSchema(msg, schemaOptions{})
+	inputProps := input["properties"].(map[string]any)
+	if _, ok := inputProps["createTime"]; ok {
+		t.Errorf("input schema has the output-only field: %v", inputProps)
+	}
+	if !reflect.DeepEqual(input["required"], []string{"name"}) {
+		t.Errorf("input required = %v, want [name]", input["required"])
+	}
+	if got := inputProps["count"]; !reflect.DeepEqual(got, map[string]any{"type": "integer", "format": "int64"}) {
+		t.Errorf("input int64 = %v", got)
+	}
+
+	output := buildRootSchema(msg, schemaOptions{output: true, protoNames: true})
+	outputProps := output["properties"].(map[string]any)
+	if _, ok := outputProps["create_time"]; !ok {
+		t.Errorf("output schema is missing the output-only field: %v", outputProps)
+	}
+	if _, ok := output["required"]; ok {
+		t.Errorf("output schema requires %v", output["required"])
+	}
+	if got := outputProps["count"]; !reflect.DeepEqual(got, map[string]any{"type": "string", "format": "int64"}) {
+		t.Errorf("output int64 = %v", got)
+	}
+
+	get := &gatewayv1.MethodOptions{Tool: &gatewayv1.Tool{Name: "tasks.get", UseEnumNumbers: true}}
+	src, err := generate(t, taskFile(nil, method("GetTask", "Task", "ImportResult", get)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, src,
+		`OutputSchema: map[string]any{ "additionalProperties": false, "properties": map[string]any{ "imported": map[string]any{ "format": "int32", "type": "integer", }, }, "type": "object", },`,
+		`JSONOptions: &runtime.ToolJSONOptions{UseEnumNumbers: proto.Bool(true)},`,
+	)
+}
+
+func TestEnumSchema(t *testing.T) {
+	open := taskMessage(t, taskFile(nil))
+	closedFile := taskFile(nil)
+	closedFile.Syntax = proto.String("proto2")
+	closed := taskMessage(t, closedFile)
+
+	for _, tc := range []struct {
+		name string
+		msg  *protogen.Message
+		opts schemaOptions
+		want map[string]any
+	}{
+		{"input", open, schemaOptions{}, map[string]any{"type": "string", "enum": []string{"STATUS_OPEN", "STATUS_DONE"}}},
+		// An open enum field can hold numbers the enum does not declare.
+		{"open output", open, schemaOptions{output: true}, map[string]any{"type": []any{"string", "integer"}}},
+		{"open output numbers", open, schemaOptions{output: true, enumNumbers: true}, map[string]any{"type": "integer"}},
+		{"closed output", closed, schemaOptions{output: true}, map[string]any{"type": "string", "enum": []string{"STATUS_UNSPECIFIED", "STATUS_OPEN", "STATUS_DONE"}}},
+		{"closed output numbers", closed, schemaOptions{output: true, enumNumbers: true}, map[string]any{"type": "integer", "enum": []any{0, 1, 2}}},
+	} {
+		props := buildRootSchema(tc.msg, tc.opts)["properties"].(map[string]any)
+		if got := props["status"]; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -5,7 +5,6 @@ package greeter
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/linkbreakers-com/grpc-mcp-gateway/runtime"
 )
@@ -32,17 +31,27 @@ func RegisterGreeterMCPHandler(mux *runtime.MCPServeMux, client GreeterClient) {
 			},
 			"type": "object",
 		},
+		InputDescriptor: (&HelloRequest{}).ProtoReflect().Descriptor(),
+		OutputSchema: map[string]any{
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"message": map[string]any{
+					"type": "string",
+				},
+			},
+			"type": "object",
+		},
 		ReadOnly: true,
 		Handler: func(ctx context.Context, args map[string]any) (any, error) {
 			req := &HelloRequest{}
-			if err := runtime.DecodeArgs(args, req); err != nil {
-				return nil, fmt.Errorf("invalid arguments: %v", err)
+			if err := runtime.DecodeArgsContext(ctx, args, req); err != nil {
+				return nil, err
 			}
-			resp, err := client.SayHello(ctx, req)
+			resp, err := client.SayHello(ctx, req, runtime.ResponseMetadata(ctx)...)
 			if err != nil {
 				return nil, err
 			}
-			return runtime.EncodeProto(resp)
+			return runtime.EncodeProtoContext(ctx, resp)
 		},
 	})
 }
@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/linkbreakers-com/grpc-mcp-gateway/runtime"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -28,17 +27,22 @@ func RegisterEchoServiceMCPHandler(mux *runtime.MCPServeMux, client EchoServiceC
 			"additionalProperties": true,
 			"type":                 "object",
 		},
+		InputDescriptor: (&structpb.Struct{}).ProtoReflect().Descriptor(),
+		OutputSchema: map[string]any{
+			"additionalProperties": true,
+			"type":                 "object",
+		},
 		ReadOnly: true,
 		Handler: func(ctx context.Context, args map[string]any) (any, error) {
 			req := &structpb.Struct{}
-			if err := runtime.DecodeArgs(args, req); err != nil {
-				return nil, fmt.Errorf("invalid arguments: %v", err)
+			if err := runtime.DecodeArgsContext(ctx, args, req); err != nil {
+				return nil, err
 			}
-			resp, err := client.Echo(ctx, req)
+			resp, err := client.Echo(ctx, req, runtime.ResponseMetadata(ctx)...)
 			if err != nil {
 				return nil, err
 			}
-			return runtime.EncodeProto(resp)
+			return runtime.EncodeProtoContext(ctx, resp)
 		},
 	})
 }
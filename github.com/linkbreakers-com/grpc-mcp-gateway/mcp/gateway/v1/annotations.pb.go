@@ -23,15 +23,25 @@ const (
 )
 
 type Tool struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	ReadOnly      bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Idempotent    bool                   `protobuf:"varint,5,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
-	Destructive   bool                   `protobuf:"varint,6,opt,name=destructive,proto3" json:"destructive,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Title             string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ReadOnly          bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Idempotent        bool                   `protobuf:"varint,5,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+	Destructive       bool                   `protobuf:"varint,6,opt,name=destructive,proto3" json:"destructive,omitempty"`
+	StreamMaxMessages uint32                 `protobuf:"varint,7,opt,name=stream_max_messages,json=streamMaxMessages,proto3" json:"stream_max_messages,omitempty"`
+	StreamMaxDuration string                 `protobuf:"bytes,8,opt,name=stream_max_duration,json=streamMaxDuration,proto3" json:"stream_max_duration,omitempty"`
+	MaxItems          uint32                 `protobuf:"varint,9,opt,name=max_items,json=maxItems,proto3" json:"max_items,omitempty"`
+	Timeout           string                 `protobuf:"bytes,10,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	SkipValidation    bool                   `protobuf:"varint,11,opt,name=skip_validation,json=skipValidation,proto3" json:"skip_validation,omitempty"`
+	EmitUnpopulated   bool                   `protobuf:"varint,12,opt,name=emit_unpopulated,json=emitUnpopulated,proto3" json:"emit_unpopulated,omitempty"`
+	UseProtoNames     bool                   `protobuf:"varint,13,opt,name=use_proto_names,json=useProtoNames,proto3" json:"use_proto_names,omitempty"`
+	UseEnumNumbers    bool                   `protobuf:"varint,14,opt,name=use_enum_numbers,json=useEnumNumbers,proto3" json:"use_enum_numbers,omitempty"`
+	DiscardUnknown    bool                   `protobuf:"varint,15,opt,name=discard_unknown,json=discardUnknown,proto3" json:"discard_unknown,omitempty"`
+	CoerceArgs        bool                   `protobuf:"varint,16,opt,name=coerce_args,json=coerceArgs,proto3" json:"coerce_args,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Tool) Reset() {
@@ -106,16 +116,163 @@ func (x *Tool) GetDestructive() bool {
 	return false
 }
 
+func (x *Tool) GetStreamMaxMessages() uint32 {
+	if x != nil {
+		return x.StreamMaxMessages
+	}
+	return 0
+}
+
+func (x *Tool) GetStreamMaxDuration() string {
+	if x != nil {
+		return x.StreamMaxDuration
+	}
+	return ""
+}
+
+func (x *Tool) GetMaxItems() uint32 {
+	if x != nil {
+		return x.MaxItems
+	}
+	return 0
+}
+
+func (x *Tool) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *Tool) GetSkipValidation() bool {
+	if x != nil {
+		return x.SkipValidation
+	}
+	return false
+}
+
+func (x *Tool) GetEmitUnpopulated() bool {
+	if x != nil {
+		return x.EmitUnpopulated
+	}
+	return false
+}
+
+func (x *Tool) GetUseProtoNames() bool {
+	if x != nil {
+		return x.UseProtoNames
+	}
+	return false
+}
+
+func (x *Tool) GetUseEnumNumbers() bool {
+	if x != nil {
+		return x.UseEnumNumbers
+	}
+	return false
+}
+
+func (x *Tool) GetDiscardUnknown() bool {
+	if x != nil {
+		return x.DiscardUnknown
+	}
+	return false
+}
+
+func (x *Tool) GetCoerceArgs() bool {
+	if x != nil {
+		return x.CoerceArgs
+	}
+	return false
+}
+
+type Resource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UriTemplate   string                 `protobuf:"bytes,1,opt,name=uri_template,json=uriTemplate,proto3" json:"uri_template,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	MimeType      string                 `protobuf:"bytes,5,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resource) Reset() {
+	*x = Resource{}
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resource) ProtoMessage() {}
+
+func (x *Resource) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resource.ProtoReflect.Descriptor instead.
+func (*Resource) Descriptor() ([]byte, []int) {
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Resource) GetUriTemplate() string {
+	if x != nil {
+		return x.UriTemplate
+	}
+	return ""
+}
+
+func (x *Resource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Resource) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Resource) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Resource) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
 type MethodOptions struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tool          *Tool                  `protobuf:"bytes,1,opt,name=tool,proto3" json:"tool,omitempty"`
+	Resource      *Resource              `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MethodOptions) Reset() {
 	*x = MethodOptions{}
-	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[1]
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -127,7 +284,7 @@ func (x *MethodOptions) String() string {
 func (*MethodOptions) ProtoMessage() {}
 
 func (x *MethodOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[1]
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -140,7 +297,7 @@ func (x *MethodOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MethodOptions.ProtoReflect.Descriptor instead.
 func (*MethodOptions) Descriptor() ([]byte, []int) {
-	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{1}
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *MethodOptions) GetTool() *Tool {
@@ -150,17 +307,221 @@ func (x *MethodOptions) GetTool() *Tool {
 	return nil
 }
 
+func (x *MethodOptions) GetResource() *Resource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
+}
+
+type PromptArgument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Required      bool                   `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	Values        []string               `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromptArgument) Reset() {
+	*x = PromptArgument{}
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromptArgument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromptArgument) ProtoMessage() {}
+
+func (x *PromptArgument) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromptArgument.ProtoReflect.Descriptor instead.
+func (*PromptArgument) Descriptor() ([]byte, []int) {
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PromptArgument) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PromptArgument) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PromptArgument) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *PromptArgument) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type PromptMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromptMessage) Reset() {
+	*x = PromptMessage{}
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromptMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromptMessage) ProtoMessage() {}
+
+func (x *PromptMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromptMessage.ProtoReflect.Descriptor instead.
+func (*PromptMessage) Descriptor() ([]byte, []int) {
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PromptMessage) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *PromptMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type Prompt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Arguments     []*PromptArgument      `protobuf:"bytes,4,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Messages      []*PromptMessage       `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prompt) Reset() {
+	*x = Prompt{}
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prompt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prompt) ProtoMessage() {}
+
+func (x *Prompt) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prompt.ProtoReflect.Descriptor instead.
+func (*Prompt) Descriptor() ([]byte, []int) {
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Prompt) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Prompt) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Prompt) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Prompt) GetArguments() []*PromptArgument {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+func (x *Prompt) GetMessages() []*PromptMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
 type ServiceOptions struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Prompts       []*Prompt              `protobuf:"bytes,3,rep,name=prompts,proto3" json:"prompts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ServiceOptions) Reset() {
 	*x = ServiceOptions{}
-	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[2]
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -172,7 +533,7 @@ func (x *ServiceOptions) String() string {
 func (*ServiceOptions) ProtoMessage() {}
 
 func (x *ServiceOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[2]
+	mi := &file_mcp_gateway_v1_annotations_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -185,7 +546,7 @@ func (x *ServiceOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServiceOptions.ProtoReflect.Descriptor instead.
 func (*ServiceOptions) Descriptor() ([]byte, []int) {
-	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{2}
+	return file_mcp_gateway_v1_annotations_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ServiceOptions) GetName() string {
@@ -202,6 +563,13 @@ func (x *ServiceOptions) GetVersion() string {
 	return ""
 }
 
+func (x *ServiceOptions) GetPrompts() []*Prompt {
+	if x != nil {
+		return x.Prompts
+	}
+	return nil
+}
+
 var file_mcp_gateway_v1_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
@@ -237,7 +605,7 @@ var File_mcp_gateway_v1_annotations_proto protoreflect.FileDescriptor
 
 const file_mcp_gateway_v1_annotations_proto_rawDesc = "" +
 	"\n" +
-	" mcp/gateway/v1/annotations.proto\x12\x0emcp.gateway.v1\x1a google/protobuf/descriptor.proto\"\xb1\x01\n" +
+	" mcp/gateway/v1/annotations.proto\x12\x0emcp.gateway.v1\x1a google/protobuf/descriptor.proto\"\xb8\x04\n" +
 	"\x04Tool\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -246,12 +614,46 @@ const file_mcp_gateway_v1_annotations_proto_rawDesc = "" +
 	"\n" +
 	"idempotent\x18\x05 \x01(\bR\n" +
 	"idempotent\x12 \n" +
-	"\vdestructive\x18\x06 \x01(\bR\vdestructive\"9\n" +
+	"\vdestructive\x18\x06 \x01(\bR\vdestructive\x12.\n" +
+	"\x13stream_max_messages\x18\a \x01(\rR\x11streamMaxMessages\x12.\n" +
+	"\x13stream_max_duration\x18\b \x01(\tR\x11streamMaxDuration\x12\x1b\n" +
+	"\tmax_items\x18\t \x01(\rR\bmaxItems\x12\x18\n" +
+	"\atimeout\x18\n" +
+	" \x01(\tR\atimeout\x12'\n" +
+	"\x0fskip_validation\x18\v \x01(\bR\x0eskipValidation\x12)\n" +
+	"\x10emit_unpopulated\x18\f \x01(\bR\x0femitUnpopulated\x12&\n" +
+	"\x0fuse_proto_names\x18\r \x01(\bR\ruseProtoNames\x12(\n" +
+	"\x10use_enum_numbers\x18\x0e \x01(\bR\x0euseEnumNumbers\x12'\n" +
+	"\x0fdiscard_unknown\x18\x0f \x01(\bR\x0ediscardUnknown\x12\x1f\n" +
+	"\vcoerce_args\x18\x10 \x01(\bR\n" +
+	"coerceArgs\"\x96\x01\n" +
+	"\bResource\x12!\n" +
+	"\furi_template\x18\x01 \x01(\tR\vuriTemplate\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tmime_type\x18\x05 \x01(\tR\bmimeType\"o\n" +
 	"\rMethodOptions\x12(\n" +
-	"\x04tool\x18\x01 \x01(\v2\x14.mcp.gateway.v1.ToolR\x04tool\">\n" +
+	"\x04tool\x18\x01 \x01(\v2\x14.mcp.gateway.v1.ToolR\x04tool\x124\n" +
+	"\bresource\x18\x02 \x01(\v2\x18.mcp.gateway.v1.ResourceR\bresource\"z\n" +
+	"\x0ePromptArgument\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x12\x16\n" +
+	"\x06values\x18\x04 \x03(\tR\x06values\"7\n" +
+	"\rPromptMessage\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\xcd\x01\n" +
+	"\x06Prompt\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12<\n" +
+	"\targuments\x18\x04 \x03(\v2\x1e.mcp.gateway.v1.PromptArgumentR\targuments\x129\n" +
+	"\bmessages\x18\x05 \x03(\v2\x1d.mcp.gateway.v1.PromptMessageR\bmessages\"p\n" +
 	"\x0eServiceOptions\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\tR\aversion:Q\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x120\n" +
+	"\aprompts\x18\x03 \x03(\v2\x16.mcp.gateway.v1.PromptR\aprompts:Q\n" +
 	"\x03mcp\x12\x1e.google.protobuf.MethodOptions\x18\xa2\x90\x03 \x01(\v2\x1d.mcp.gateway.v1.MethodOptionsR\x03mcp:b\n" +
 	"\vmcp_service\x12\x1f.google.protobuf.ServiceOptions\x18\xa3\x90\x03 \x01(\v2\x1e.mcp.gateway.v1.ServiceOptionsR\n" +
 	"mcpServiceBLZJgithub.com/linkbreakers-com/grpc-mcp-gateway/mcp/gateway/v1;mcp_gateway_v1b\x06proto3"
@@ -268,25 +670,33 @@ func file_mcp_gateway_v1_annotations_proto_rawDescGZIP() []byte {
 	return file_mcp_gateway_v1_annotations_proto_rawDescData
 }
 
-var file_mcp_gateway_v1_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_mcp_gateway_v1_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_mcp_gateway_v1_annotations_proto_goTypes = []any{
 	(*Tool)(nil),                        // 0: mcp.gateway.v1.Tool
-	(*MethodOptions)(nil),               // 1: mcp.gateway.v1.MethodOptions
-	(*ServiceOptions)(nil),              // 2: mcp.gateway.v1.ServiceOptions
-	(*descriptorpb.MethodOptions)(nil),  // 3: google.protobuf.MethodOptions
-	(*descriptorpb.ServiceOptions)(nil), // 4: google.protobuf.ServiceOptions
+	(*Resource)(nil),                    // 1: mcp.gateway.v1.Resource
+	(*MethodOptions)(nil),               // 2: mcp.gateway.v1.MethodOptions
+	(*PromptArgument)(nil),              // 3: mcp.gateway.v1.PromptArgument
+	(*PromptMessage)(nil),               // 4: mcp.gateway.v1.PromptMessage
+	(*Prompt)(nil),                      // 5: mcp.gateway.v1.Prompt
+	(*ServiceOptions)(nil),              // 6: mcp.gateway.v1.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),  // 7: google.protobuf.MethodOptions
+	(*descriptorpb.ServiceOptions)(nil), // 8: google.protobuf.ServiceOptions
 }
 var file_mcp_gateway_v1_annotations_proto_depIdxs = []int32{
 	0, // 0: mcp.gateway.v1.MethodOptions.tool:type_name -> mcp.gateway.v1.Tool
-	3, // 1: mcp.gateway.v1.mcp:extendee -> google.protobuf.MethodOptions
-	4, // 2: mcp.gateway.v1.mcp_service:extendee -> google.protobuf.ServiceOptions
-	1, // 3: mcp.gateway.v1.mcp:type_name -> mcp.gateway.v1.MethodOptions
-	2, // 4: mcp.gateway.v1.mcp_service:type_name -> mcp.gateway.v1.ServiceOptions
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	3, // [3:5] is the sub-list for extension type_name
-	1, // [1:3] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1, // 1: mcp.gateway.v1.MethodOptions.resource:type_name -> mcp.gateway.v1.Resource
+	3, // 2: mcp.gateway.v1.Prompt.arguments:type_name -> mcp.gateway.v1.PromptArgument
+	4, // 3: mcp.gateway.v1.Prompt.messages:type_name -> mcp.gateway.v1.PromptMessage
+	5, // 4: mcp.gateway.v1.ServiceOptions.prompts:type_name -> mcp.gateway.v1.Prompt
+	7, // 5: mcp.gateway.v1.mcp:extendee -> google.protobuf.MethodOptions
+	8, // 6: mcp.gateway.v1.mcp_service:extendee -> google.protobuf.ServiceOptions
+	2, // 7: mcp.gateway.v1.mcp:type_name -> mcp.gateway.v1.MethodOptions
+	6, // 8: mcp.gateway.v1.mcp_service:type_name -> mcp.gateway.v1.ServiceOptions
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	7, // [7:9] is the sub-list for extension type_name
+	5, // [5:7] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_mcp_gateway_v1_annotations_proto_init() }
@@ -300,7 +710,7 @@ func file_mcp_gateway_v1_annotations_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_gateway_v1_annotations_proto_rawDesc), len(file_mcp_gateway_v1_annotations_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   7,
 			NumExtensions: 2,
 			NumServices:   0,
 		},
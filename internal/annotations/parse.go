@@ -18,26 +18,89 @@ type ToolOptions struct {
 	ReadOnly    bool
 	Idempotent  bool
 	Destructive bool
+	// StreamMaxMessages and StreamMaxDuration bound how much of a server
+	// stream is collected. StreamMaxDuration is a Go duration string.
+	StreamMaxMessages uint32
+	StreamMaxDuration string
+	// MaxItems caps the items sent on a client stream.
+	MaxItems uint32
+	// Timeout bounds the tool call, as a Go duration string.
+	Timeout string
+	// SkipValidation turns off runtime argument validation.
+	SkipValidation bool
+	// EmitUnpopulated, UseProtoNames, UseEnumNumbers and DiscardUnknown
+	// select the protojson options for the tool.
+	EmitUnpopulated bool
+	UseProtoNames   bool
+	UseEnumNumbers  bool
+	DiscardUnknown  bool
+	// CoerceArgs turns on runtime argument coercion.
+	CoerceArgs bool
+}
+
+type ResourceOptions struct {
+	URITemplate string
+	Name        string
+	Title       string
+	Description string
+	MIMEType    string
 }
 
 type ServiceOptions struct {
 	Name    string
 	Version string
+	Prompts []PromptOptions
+}
+
+type PromptOptions struct {
+	Name        string
+	Title       string
+	Description string
+	Arguments   []PromptArgumentOptions
+	Messages    []PromptMessageOptions
+}
+
+type PromptArgumentOptions struct {
+	Name        string
+	Description string
+	Required    bool
+	Values      []string
+}
+
+type PromptMessageOptions struct {
+	Role string
+	Text string
 }
 
 func ToolFromMethod(method protoreflect.MethodDescriptor) (ToolOptions, bool) {
-	opts, ok := method.Options().(*descriptorpb.MethodOptions)
-	if !ok || opts == nil {
-		return ToolOptions{}, false
-	}
-	raw := opts.ProtoReflect().GetUnknown()
-	ext := findExtension(raw, methodOptionFieldNumber)
+	ext := methodExtension(method)
 	if ext == nil {
 		return ToolOptions{}, false
 	}
 	return parseMethodOptions(ext)
 }
 
+func ResourceFromMethod(method protoreflect.MethodDescriptor) (ResourceOptions, bool) {
+	ext := methodExtension(method)
+	if ext == nil {
+		return ResourceOptions{}, false
+	}
+	b := findExtension(ext, 2)
+	if b == nil {
+		return ResourceOptions{}, false
+	}
+	return parseResourceOptions(b), true
+}
+
+func methodExtension(method protoreflect.MethodDescriptor) []byte {
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+	raw := opts.ProtoReflect().GetUnknown()
+	return findExtension(raw, methodOptionFieldNumber)
+}
+
 func ServiceFromService(service protoreflect.ServiceDescriptor) (ServiceOptions, bool) {
 	opts, ok := service.Options().(*descriptorpb.ServiceOptions)
 	if !ok || opts == nil {
@@ -153,6 +216,16 @@ func parseServiceOptions(raw []byte) (ServiceOptions, bool) {
 			}
 			out.Version = string(b)
 			raw = raw[m:]
+		case 3:
+			if typ != protowire.BytesType {
+				return ServiceOptions{}, false
+			}
+			b, m := protowire.ConsumeBytes(raw)
+			if m < 0 {
+				return ServiceOptions{}, false
+			}
+			out.Prompts = append(out.Prompts, parsePromptOptions(b))
+			raw = raw[m:]
 		default:
 			skip, err := consumeField(typ, raw)
 			if err != nil {
@@ -233,6 +306,221 @@ func parseToolOptions(raw []byte) ToolOptions {
 			}
 			out.Destructive = v != 0
 			raw = raw[m:]
+		case 7:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.StreamMaxMessages = uint32(v)
+			raw = raw[m:]
+		case 8:
+			if typ != protowire.BytesType {
+				return out
+			}
+			b, m := protowire.ConsumeBytes(raw)
+			if m < 0 {
+				return out
+			}
+			out.StreamMaxDuration = string(b)
+			raw = raw[m:]
+		case 9:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.MaxItems = uint32(v)
+			raw = raw[m:]
+		case 10:
+			if typ != protowire.BytesType {
+				return out
+			}
+			b, m := protowire.ConsumeBytes(raw)
+			if m < 0 {
+				return out
+			}
+			out.Timeout = string(b)
+			raw = raw[m:]
+		case 11:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.SkipValidation = v != 0
+			raw = raw[m:]
+		case 12:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.EmitUnpopulated = v != 0
+			raw = raw[m:]
+		case 13:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.UseProtoNames = v != 0
+			raw = raw[m:]
+		case 14:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.UseEnumNumbers = v != 0
+			raw = raw[m:]
+		case 15:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.DiscardUnknown = v != 0
+			raw = raw[m:]
+		case 16:
+			if typ != protowire.VarintType {
+				return out
+			}
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.CoerceArgs = v != 0
+			raw = raw[m:]
+		default:
+			skip, err := consumeField(typ, raw)
+			if err != nil {
+				return out
+			}
+			raw = raw[skip:]
+		}
+	}
+	return out
+}
+
+func parseResourceOptions(raw []byte) ResourceOptions {
+	var out ResourceOptions
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return out
+		}
+		raw = raw[n:]
+		if typ != protowire.BytesType || num < 1 || num > 5 {
+			skip, err := consumeField(typ, raw)
+			if err != nil {
+				return out
+			}
+			raw = raw[skip:]
+			continue
+		}
+		b, m := protowire.ConsumeBytes(raw)
+		if m < 0 {
+			return out
+		}
+		switch num {
+		case 1:
+			out.URITemplate = string(b)
+		case 2:
+			out.Name = string(b)
+		case 3:
+			out.Title = string(b)
+		case 4:
+			out.Description = string(b)
+		case 5:
+			out.MIMEType = string(b)
+		}
+		raw = raw[m:]
+	}
+	return out
+}
+
+func parsePromptOptions(raw []byte) PromptOptions {
+	var out PromptOptions
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return out
+		}
+		raw = raw[n:]
+		if typ != protowire.BytesType || num < 1 || num > 5 {
+			skip, err := consumeField(typ, raw)
+			if err != nil {
+				return out
+			}
+			raw = raw[skip:]
+			continue
+		}
+		b, m := protowire.ConsumeBytes(raw)
+		if m < 0 {
+			return out
+		}
+		switch num {
+		case 1:
+			out.Name = string(b)
+		case 2:
+			out.Title = string(b)
+		case 3:
+			out.Description = string(b)
+		case 4:
+			out.Arguments = append(out.Arguments, parsePromptArgumentOptions(b))
+		case 5:
+			out.Messages = append(out.Messages, parsePromptMessageOptions(b))
+		}
+		raw = raw[m:]
+	}
+	return out
+}
+
+func parsePromptArgumentOptions(raw []byte) PromptArgumentOptions {
+	var out PromptArgumentOptions
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return out
+		}
+		raw = raw[n:]
+		switch {
+		case (num == 1 || num == 2 || num == 4) && typ == protowire.BytesType:
+			b, m := protowire.ConsumeBytes(raw)
+			if m < 0 {
+				return out
+			}
+			switch num {
+			case 1:
+				out.Name = string(b)
+			case 2:
+				out.Description = string(b)
+			case 4:
+				out.Values = append(out.Values, string(b))
+			}
+			raw = raw[m:]
+		case num == 3 && typ == protowire.VarintType:
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return out
+			}
+			out.Required = v != 0
+			raw = raw[m:]
 		default:
 			skip, err := consumeField(typ, raw)
 			if err != nil {
@@ -244,6 +532,36 @@ func parseToolOptions(raw []byte) ToolOptions {
 	return out
 }
 
+func parsePromptMessageOptions(raw []byte) PromptMessageOptions {
+	var out PromptMessageOptions
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return out
+		}
+		raw = raw[n:]
+		if (num != 1 && num != 2) || typ != protowire.BytesType {
+			skip, err := consumeField(typ, raw)
+			if err != nil {
+				return out
+			}
+			raw = raw[skip:]
+			continue
+		}
+		b, m := protowire.ConsumeBytes(raw)
+		if m < 0 {
+			return out
+		}
+		if num == 1 {
+			out.Role = string(b)
+		} else {
+			out.Text = string(b)
+		}
+		raw = raw[m:]
+	}
+	return out
+}
+
 func consumeField(typ protowire.Type, raw []byte) (int, error) {
 	switch typ {
 	case protowire.VarintType:
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// rpcMessage is one decoded entry of a JSON-RPC payload. Entries that are not
+// valid request objects carry the error response to return in their place.
+type rpcMessage struct {
+	req     *MCPRequest
+	invalid *MCPResponse
+}
+
+// decodeMessages parses a single JSON-RPC message or a batch array. The
+// returned bool reports whether data was a batch.
+func decodeMessages(data []byte) ([]rpcMessage, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		var req MCPRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, false, err
+		}
+		return []rpcMessage{{req: &req}}, false, nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, true, err
+	}
+	msgs := make([]rpcMessage, len(raw))
+	for i, entry := range raw {
+		var req MCPRequest
+		if err := json.Unmarshal(entry, &req); err != nil {
+			msgs[i].invalid = errorResponse(nil, -32600, "Invalid Request")
+			continue
+		}
+		msgs[i].req = &req
+	}
+	return msgs, true, nil
+}
+
+// dispatchAll dispatches every message and returns the responses, in message
+// order, for the entries that expect one. Tool calls run concurrently.
+func (mux *MCPServeMux) dispatchAll(ctx context.Context, msgs []rpcMessage) []*MCPResponse {
+	slots := make([]*MCPResponse, len(msgs))
+	var wg sync.WaitGroup
+	for i, msg := range msgs {
+		if msg.invalid != nil {
+			slots[i] = msg.invalid
+			continue
+		}
+		req := msg.req
+		mux.requestLogger(ctx, req)
+		if !expectsResponse(req) {
+			mux.dispatch(ctx, req)
+			continue
+		}
+		if req.Method == "tools/call" {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				slots[i] = mux.dispatch(ctx, req)
+			}(i)
+			continue
+		}
+		slots[i] = mux.dispatch(ctx, req)
+	}
+	wg.Wait()
+
+	responses := make([]*MCPResponse, 0, len(slots))
+	for _, resp := range slots {
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	return responses
+}
+
+// needsResponse reports whether any entry of msgs will produce a response.
+func needsResponse(msgs []rpcMessage) bool {
+	for _, msg := range msgs {
+		if msg.invalid != nil || expectsResponse(msg.req) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMethod reports whether any request in msgs calls method.
+func hasMethod(msgs []rpcMessage, method string) bool {
+	for _, msg := range msgs {
+		if msg.req != nil && msg.req.Method == method && msg.req.ID != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	mux := newTestMux()
+	rec := postJSON(t, mux, []any{
+		rpc(1, "tools/list", nil),
+		rpc(nil, "notifications/initialized", nil),
+		rpc(2, "tools/call", map[string]any{"name": "echo", "arguments": map[string]any{}}),
+		42,
+	}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var responses []MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("decode batch response: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(responses))
+	}
+	if responses[0].ID != float64(1) || responses[1].ID != float64(2) {
+		t.Fatalf("responses out of order: %+v", responses)
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != -32600 {
+		t.Fatalf("expected invalid request error, got %+v", responses[2])
+	}
+
+	rec = postJSON(t, mux, []any{rpc(nil, "notifications/initialized", nil)}, nil)
+	if rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Fatalf("notification-only batch: status %d, body %q", rec.Code, rec.Body.String())
+	}
+}
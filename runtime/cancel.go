@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+)
+
+// requestKey identifies an in-flight request. JSON-RPC IDs are only unique
+// within a session, so requests outside one are never tracked: any stateless
+// client could otherwise cancel another's call by reusing its ID.
+type requestKey struct {
+	session *session
+	id      string
+}
+
+// inflightCall is a tool call that can be cancelled by the client.
+type inflightCall struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
+// newRequestKey returns the key of the request with the given ID in the
+// session of ctx. It returns false outside a session.
+func newRequestKey(ctx context.Context, id any) (requestKey, bool) {
+	sess := sessionFromContext(ctx)
+	if sess == nil {
+		return requestKey{}, false
+	}
+	b, err := json.Marshal(id)
+	if err != nil {
+		return requestKey{}, false
+	}
+	return requestKey{session: sess, id: string(b)}, true
+}
+
+// trackRequest registers a cancellable context for the request with the given
+// ID. A request reusing the ID of one still in flight is not registered, so it
+// cannot take over the earlier call's cancellation. The returned func must be
+// called once the request has completed.
+func (mux *MCPServeMux) trackRequest(ctx context.Context, id any) (context.Context, *inflightCall, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	call := &inflightCall{cancel: cancel}
+	key, ok := newRequestKey(ctx, id)
+	if !ok {
+		return ctx, call, cancel
+	}
+
+	mux.inflightMu.Lock()
+	if _, exists := mux.inflight[key]; exists {
+		mux.inflightMu.Unlock()
+		return ctx, call, cancel
+	}
+	mux.inflight[key] = call
+	mux.inflightMu.Unlock()
+
+	return ctx, call, func() {
+		mux.inflightMu.Lock()
+		if mux.inflight[key] == call {
+			delete(mux.inflight, key)
+		}
+		mux.inflightMu.Unlock()
+		cancel()
+	}
+}
+
+// handleCancelled cancels the in-flight request named by a
+// notifications/cancelled message. Unknown or completed requests, and
+// notifications sent outside a session, are ignored.
+func (mux *MCPServeMux) handleCancelled(ctx context.Context, params map[string]interface{}) {
+	id, ok := params["requestId"]
+	if !ok {
+		return
+	}
+	key, ok := newRequestKey(ctx, id)
+	if !ok {
+		return
+	}
+
+	mux.inflightMu.Lock()
+	call := mux.inflight[key]
+	mux.inflightMu.Unlock()
+
+	if call != nil {
+		call.cancelled.Store(true)
+		call.cancel()
+	}
+}
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCancelledToolCall(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "slow",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	})
+
+	pr, pw := io.Pipe()
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- mux.ServeConn(context.Background(), pr, &out) }()
+
+	fmt.Fprintln(pw, `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"slow"}}`)
+	<-started
+	fmt.Fprintln(pw, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1"}}`)
+	<-cancelled
+	fmt.Fprintln(pw, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	pw.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+
+	if got := strings.TrimSpace(out.String()); got != `{"jsonrpc":"2.0","id":2,"result":{}}` {
+		t.Fatalf("unexpected output: %s", got)
+	}
+}
+
+func TestStatelessCancelIgnored(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "slow",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			close(started)
+			select {
+			case <-release:
+				return "done", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+
+	result := make(chan *httptest.ResponseRecorder, 1)
+	go func() { result <- postJSON(t, mux, rpc("call-1", "tools/call", map[string]any{"name": "slow"}), nil) }()
+	<-started
+	rec := postJSON(t, mux, rpc(nil, "notifications/cancelled", map[string]any{"requestId": "call-1"}), nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("cancel notification: got status %d, want 202", rec.Code)
+	}
+	close(release)
+
+	if body := (<-result).Body.String(); !strings.Contains(body, `"text":"done"`) {
+		t.Fatalf("stateless call was cancelled by another request: %s", body)
+	}
+}
+
+func TestCancelledHTTPRequest(t *testing.T) {
+	started := make(chan struct{})
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithSessions())
+	mux.RegisterTool(&ToolHandler{
+		Name: "slow",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	rec := postJSON(t, mux, rpc(1, "initialize", nil), nil)
+	withID := http.Header{SessionIDHeader: {rec.Header().Get(SessionIDHeader)}}
+
+	result := make(chan *httptest.ResponseRecorder, 1)
+	go func() { result <- postJSON(t, mux, rpc("call-1", "tools/call", map[string]any{"name": "slow"}), withID) }()
+	<-started
+	postJSON(t, mux, rpc(nil, "notifications/cancelled", map[string]any{"requestId": "call-1"}), withID)
+
+	rec = <-result
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Fatalf("cancelled request: got status %d, body %q; want an empty 200", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,248 @@
+package runtime
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ArgCoercion records an argument value rewritten by CoerceArgs. Pointer is
+// the JSON pointer of the value.
+type ArgCoercion struct {
+	Pointer string `json:"pointer"`
+	From    any    `json:"from"`
+	To      any    `json:"to"`
+}
+
+// WithArgCoercion turns on argument coercion for every tool. See
+// ToolHandler.CoerceArgs.
+func WithArgCoercion() Option {
+	return func(mux *MCPServeMux) {
+		mux.coerceArgs = true
+	}
+}
+
+// CoerceArgs rewrites, in place, argument values that models commonly send
+// in a form protojson rejects or misreads, guided by the request message
+// descriptor:
+//
+//   - "42" for 32-bit integer and floating point fields becomes 42;
+//   - "true" and "false" for bool fields become booleans;
+//   - enum names in another case or without their prefix ("done" for
+//     TASK_STATUS_DONE) become the full name when exactly one value matches;
+//   - numbers for 64-bit integer fields become decimal strings;
+//   - a single value for a repeated field becomes a one-element list.
+//
+// It returns what was changed. Values it cannot make sense of are left for
+// validation and decoding to report.
+func CoerceArgs(args map[string]any, desc protoreflect.MessageDescriptor) []ArgCoercion {
+	if desc == nil {
+		return nil
+	}
+	var c coercer
+	c.message(args, desc, "")
+	return c.coercions
+}
+
+type coercer struct {
+	coercions []ArgCoercion
+}
+
+func (c *coercer) record(pointer string, from, to any) {
+	c.coercions = append(c.coercions, ArgCoercion{Pointer: pointer, From: from, To: to})
+}
+
+func (c *coercer) message(obj map[string]any, desc protoreflect.MessageDescriptor, pointer string) {
+	if isWellKnownMessage(desc) {
+		return
+	}
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		field := desc.Fields().ByJSONName(key)
+		if field == nil {
+			field = desc.Fields().ByName(protoreflect.Name(key))
+		}
+		if field == nil || value == nil {
+			continue
+		}
+		obj[key] = c.field(value, field, pointer+"/"+escapePointer(key))
+	}
+}
+
+func (c *coercer) field(value any, field protoreflect.FieldDescriptor, pointer string) any {
+	switch {
+	case field.IsList():
+		list, ok := value.([]any)
+		if !ok {
+			list = []any{value}
+			c.record(pointer, value, list)
+		}
+		for i, item := range list {
+			if item != nil {
+				list[i] = c.single(item, field, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+		return list
+	case field.IsMap():
+		if m, ok := value.(map[string]any); ok {
+			for _, key := range sortedKeys(m) {
+				if item := m[key]; item != nil {
+					m[key] = c.single(item, field.MapValue(), pointer+"/"+escapePointer(key))
+				}
+			}
+		}
+		return value
+	default:
+		return c.single(value, field, pointer)
+	}
+}
+
+// single coerces one value of field, ignoring its cardinality.
+func (c *coercer) single(value any, field protoreflect.FieldDescriptor, pointer string) any {
+	out, ok := coerceScalar(value, field)
+	if ok {
+		c.record(pointer, value, out)
+		return out
+	}
+	if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+		if obj, isObj := value.(map[string]any); isObj {
+			c.message(obj, field.Message(), pointer)
+		}
+	}
+	return value
+}
+
+// coerceScalar returns the coerced form of value and true if it changed.
+func coerceScalar(value any, field protoreflect.FieldDescriptor) (any, bool) {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		if s, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(s)) {
+			case "true":
+				return true, true
+			case "false":
+				return false, true
+			}
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if s, ok := value.(string); ok {
+			if n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32); err == nil {
+				return float64(n), true
+			}
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if s, ok := value.(string); ok {
+			if n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32); err == nil {
+				return float64(n), true
+			}
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if s, ok := value.(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				return f, true
+			}
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if f, ok := value.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'f', -1, 64), true
+		}
+	case protoreflect.EnumKind:
+		if s, ok := value.(string); ok {
+			if name, ok := matchEnumName(s, field.Enum()); ok && name != s {
+				return name, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// matchEnumName finds the enum value s refers to, ignoring case and the
+// value-name prefix, e.g. "done" or "Status-Done" for TASK_STATUS_DONE. A
+// match must be unique.
+func matchEnumName(s string, enum protoreflect.EnumDescriptor) (string, bool) {
+	values := enum.Values()
+	if values.ByName(protoreflect.Name(s)) != nil {
+		return s, true
+	}
+	want := strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(s)))
+	if want == "" {
+		return "", false
+	}
+	if v := values.ByName(protoreflect.Name(want)); v != nil {
+		return want, true
+	}
+	var match string
+	for i := 0; i < values.Len(); i++ {
+		name := string(values.Get(i).Name())
+		if strings.HasSuffix(name, "_"+want) {
+			if match != "" {
+				return "", false
+			}
+			match = name
+		}
+	}
+	return match, match != ""
+}
+
+// sortedKeys returns the keys of m in order, so coercions are reported
+// deterministically.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isWellKnownMessage reports whether desc has a special JSON form, such as
+// Timestamp or Struct, that coercion must leave alone.
+func isWellKnownMessage(desc protoreflect.MessageDescriptor) bool {
+	if desc.ParentFile().Package() != "google.protobuf" {
+		return false
+	}
+	switch desc.Name() {
+	case "Any", "Timestamp", "Duration", "FieldMask", "Struct", "Value", "ListValue", "Empty",
+		"DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value",
+		"BoolValue", "StringValue", "BytesValue":
+		return true
+	}
+	return false
+}
+
+// coerceToolArgs coerces the arguments of tool. Client-streaming tools take
+// their request messages under "items".
+func coerceToolArgs(tool *ToolHandler, args map[string]any) []ArgCoercion {
+	desc := tool.InputDescriptor
+	if desc == nil || args == nil {
+		return nil
+	}
+	if !tool.ClientStreaming {
+		return CoerceArgs(args, desc)
+	}
+	items, _ := args["items"].([]any)
+	var c coercer
+	for i, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			c.message(obj, desc, "/items/"+strconv.Itoa(i))
+		}
+	}
+	return c.coercions
+}
+
+// addCoercions reports coercions in the _meta of a tool result.
+func addCoercions(result map[string]interface{}, coercions []ArgCoercion) {
+	if len(coercions) == 0 {
+		return
+	}
+	meta, _ := result["_meta"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		result["_meta"] = meta
+	}
+	meta["coercedArguments"] = coercions
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestArgCoercion(t *testing.T) {
+	var got *typepb.Type
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithArgCoercion())
+	mux.RegisterTool(&ToolHandler{
+		Name: "define",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"syntax": map[string]any{"type": "string", "enum": []string{"SYNTAX_PROTO2", "SYNTAX_PROTO3"}},
+				"oneofs": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+		},
+		InputDescriptor: (&typepb.Type{}).ProtoReflect().Descriptor(),
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			got = &typepb.Type{}
+			if err := DecodeArgsContext(ctx, args, got); err != nil {
+				return nil, err
+			}
+			return "ok", nil
+		},
+	})
+
+	args := map[string]any{
+		"name":   "Task",
+		"syntax": "proto3",
+		"oneofs": "choice",
+		"fields": []any{map[string]any{"kind": "string", "number": "3", "packed": "TRUE"}},
+	}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "define", "arguments": args}), latestVersion)
+	var resp struct {
+		Result struct {
+			IsError bool `json:"isError"`
+			Meta    struct {
+				CoercedArguments []ArgCoercion `json:"coercedArguments"`
+			} `json:"_meta"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result.IsError || got == nil {
+		t.Fatalf("call failed: %s", rec.Body.String())
+	}
+	want := &typepb.Type{
+		Name:   "Task",
+		Syntax: typepb.Syntax_SYNTAX_PROTO3,
+		Oneofs: []string{"choice"},
+		Fields: []*typepb.Field{{Kind: typepb.Field_TYPE_STRING, Number: 3, Packed: true}},
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("decoded %v, want %v", got, want)
+	}
+	var pointers []string
+	for _, c := range resp.Result.Meta.CoercedArguments {
+		pointers = append(pointers, c.Pointer)
+	}
+	wantPointers := "/fields/0/kind /fields/0/number /fields/0/packed /oneofs /syntax"
+	if strings.Join(pointers, " ") != wantPointers {
+		t.Errorf("coerced %v, want %s", pointers, wantPointers)
+	}
+
+	option := map[string]any{"negativeIntValue": float64(-12)}
+	coercions := CoerceArgs(option, (&descriptorpb.UninterpretedOption{}).ProtoReflect().Descriptor())
+	if len(coercions) != 1 || option["negativeIntValue"] != "-12" {
+		t.Errorf("int64 coercion: got %v, %v", option, coercions)
+	}
+}
+
+func TestArgCoercionClientStreaming(t *testing.T) {
+	desc := (&typepb.Field{}).ProtoReflect().Descriptor()
+	stream := &ToolHandler{Name: "import", InputDescriptor: desc, ClientStreaming: true}
+	args := map[string]any{"items": []any{map[string]any{"number": "3"}}}
+	if coercions := coerceToolArgs(stream, args); len(coercions) != 1 || coercions[0].Pointer != "/items/0/number" {
+		t.Errorf("client-streaming coercions: %v", coercions)
+	}
+
+	// A stray "items" list is an unknown field of a unary tool, not a stream.
+	unary := &ToolHandler{Name: "define", InputDescriptor: desc}
+	args = map[string]any{"number": "3", "items": []any{map[string]any{"number": "4"}}}
+	if coercions := coerceToolArgs(unary, args); len(coercions) != 1 || coercions[0].Pointer != "/number" {
+		t.Errorf("unary coercions: %v", coercions)
+	}
+}
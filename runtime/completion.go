@@ -0,0 +1,136 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+)
+
+// maxCompletionValues is the most values a completion/complete result may
+// carry.
+const maxCompletionValues = 100
+
+// CompletionRef identifies what an argument belongs to: a prompt or a
+// resource template.
+type CompletionRef struct {
+	// Type is "ref/prompt" or "ref/resource".
+	Type string
+	// Name is the prompt name or the resource URI template.
+	Name string
+}
+
+// PromptCompletionRef returns the reference for the arguments of a prompt.
+func PromptCompletionRef(name string) CompletionRef {
+	return CompletionRef{Type: "ref/prompt", Name: name}
+}
+
+// ResourceCompletionRef returns the reference for the variables of a
+// resource URI template.
+func ResourceCompletionRef(uriTemplate string) CompletionRef {
+	return CompletionRef{Type: "ref/resource", Name: uriTemplate}
+}
+
+// CompletionRequest is a request to complete one argument value.
+type CompletionRequest struct {
+	Ref      CompletionRef
+	Argument string
+	// Value is the partial value typed so far.
+	Value string
+	// Arguments holds values the client already resolved for other arguments.
+	Arguments map[string]string
+}
+
+// CompletionFunc returns candidate values for an argument. The mux truncates
+// the result to the 100 values allowed by the protocol.
+type CompletionFunc func(ctx context.Context, req CompletionRequest) ([]string, error)
+
+type completionKey struct {
+	ref      CompletionRef
+	argument string
+}
+
+// StaticCompletion completes from a fixed list of values, keeping those that
+// start with the typed value, ignoring case.
+func StaticCompletion(values ...string) CompletionFunc {
+	return func(ctx context.Context, req CompletionRequest) ([]string, error) {
+		prefix := strings.ToLower(req.Value)
+		var out []string
+		for _, v := range values {
+			if strings.HasPrefix(strings.ToLower(v), prefix) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+}
+
+// RegisterCompletion registers the completion source for an argument of a
+// prompt or resource template, replacing any previous source.
+func (mux *MCPServeMux) RegisterCompletion(ref CompletionRef, argument string, fn CompletionFunc) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.completions[completionKey{ref: ref, argument: argument}] = fn
+}
+
+func (mux *MCPServeMux) hasCompletions() bool {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	return len(mux.completions) > 0
+}
+
+func (mux *MCPServeMux) handleComplete(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	refParams, _ := params["ref"].(map[string]interface{})
+	argParams, _ := params["argument"].(map[string]interface{})
+	if refParams == nil || argParams == nil {
+		return nil, &MCPError{Code: -32602, Message: "Missing ref or argument"}
+	}
+
+	req := CompletionRequest{Arguments: map[string]string{}}
+	req.Ref.Type, _ = refParams["type"].(string)
+	switch req.Ref.Type {
+	case "ref/prompt":
+		req.Ref.Name, _ = refParams["name"].(string)
+	case "ref/resource":
+		req.Ref.Name, _ = refParams["uri"].(string)
+	default:
+		return nil, &MCPError{Code: -32602, Message: "Invalid ref type: " + req.Ref.Type}
+	}
+	req.Argument, _ = argParams["name"].(string)
+	req.Value, _ = argParams["value"].(string)
+	if completionCtx, ok := params["context"].(map[string]interface{}); ok {
+		if args, ok := completionCtx["arguments"].(map[string]interface{}); ok {
+			for k, v := range args {
+				if s, ok := v.(string); ok {
+					req.Arguments[k] = s
+				}
+			}
+		}
+	}
+
+	mux.mu.RLock()
+	fn := mux.completions[completionKey{ref: req.Ref, argument: req.Argument}]
+	mux.mu.RUnlock()
+
+	var values []string
+	if fn != nil {
+		var err error
+		if values, err = fn(ctx, req); err != nil {
+			return nil, &MCPError{Code: -32603, Message: err.Error()}
+		}
+	}
+
+	total := len(values)
+	if total > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+	if values == nil {
+		values = []string{}
+	}
+
+	return map[string]interface{}{
+		"completion": map[string]interface{}{
+			"values":  values,
+			"total":   total,
+			"hasMore": total > len(values),
+		},
+	}, nil
+}
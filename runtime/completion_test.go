@@ -0,0 +1,32 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	mux := newTestMux()
+	mux.RegisterCompletion(ResourceCompletionRef("tasks://status/{status}"), "status", StaticCompletion("STATUS_OPEN", "STATUS_DONE"))
+	mux.RegisterCompletion(PromptCompletionRef("triage"), "task_id", func(ctx context.Context, req CompletionRequest) ([]string, error) {
+		return []string{req.Arguments["project"] + "-1", req.Arguments["project"] + "-2"}, nil
+	})
+
+	rec := postJSON(t, mux, rpc(1, "completion/complete", map[string]any{
+		"ref":      map[string]any{"type": "ref/resource", "uri": "tasks://status/{status}"},
+		"argument": map[string]any{"name": "status", "value": "status_d"},
+	}), nil)
+	if !strings.Contains(rec.Body.String(), `"values":["STATUS_DONE"]`) {
+		t.Fatalf("unexpected completion: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, rpc(2, "completion/complete", map[string]any{
+		"ref":      map[string]any{"type": "ref/prompt", "name": "triage"},
+		"argument": map[string]any{"name": "task_id", "value": ""},
+		"context":  map[string]any{"arguments": map[string]any{"project": "apollo"}},
+	}), nil)
+	if !strings.Contains(rec.Body.String(), `"values":["apollo-1","apollo-2"]`) {
+		t.Fatalf("unexpected completion: %s", rec.Body.String())
+	}
+}
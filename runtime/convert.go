@@ -1,26 +1,129 @@
 package runtime
 
 import (
+	"context"
 	"encoding/json"
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-// ArgPreprocessor is a function that preprocesses arguments before decoding.
-// This can be used to transform enum values or perform other custom conversions.
-type ArgPreprocessor func(args map[string]any) map[string]any
+// TypeResolver resolves the message types packed in google.protobuf.Any
+// fields, and extensions.
+type TypeResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// JSONOptions controls how arguments are decoded into requests and how
+// responses are encoded. The zero value matches protojson's defaults.
+type JSONOptions struct {
+	// EmitUnpopulated includes fields holding their zero value, so that
+	// false, 0 and "" appear in results instead of being left out.
+	EmitUnpopulated bool
+	// UseProtoNames uses the proto field names (snake_case) instead of the
+	// lowerCamelCase JSON names in results. Arguments are accepted in
+	// either form.
+	UseProtoNames bool
+	// UseEnumNumbers encodes enum values as numbers instead of names.
+	UseEnumNumbers bool
+	// DiscardUnknown ignores argument fields the request message does not
+	// have instead of failing the call.
+	DiscardUnknown bool
+	// Resolver looks up the types of Any fields. Nil uses the global
+	// registry.
+	Resolver TypeResolver
+}
+
+// ToolJSONOptions overrides the mux's JSONOptions for one tool. A nil flag
+// keeps the mux's setting; a set one wins over it, whether true or false.
+type ToolJSONOptions struct {
+	EmitUnpopulated *bool
+	UseProtoNames   *bool
+	UseEnumNumbers  *bool
+	DiscardUnknown  *bool
+	// Resolver, if non-nil, replaces the mux's.
+	Resolver TypeResolver
+}
+
+// merge returns o with the flags set in tool and tool's resolver, if any, in
+// place of o's.
+func (o JSONOptions) merge(tool *ToolJSONOptions) JSONOptions {
+	if tool == nil {
+		return o
+	}
+	override := func(flag *bool, set *bool) {
+		if set != nil {
+			*flag = *set
+		}
+	}
+	override(&o.EmitUnpopulated, tool.EmitUnpopulated)
+	override(&o.UseProtoNames, tool.UseProtoNames)
+	override(&o.UseEnumNumbers, tool.UseEnumNumbers)
+	override(&o.DiscardUnknown, tool.DiscardUnknown)
+	if tool.Resolver != nil {
+		o.Resolver = tool.Resolver
+	}
+	return o
+}
+
+// sameOutputShape reports whether o and p encode results alike.
+func (o JSONOptions) sameOutputShape(p JSONOptions) bool {
+	return o.UseProtoNames == p.UseProtoNames &&
+		o.UseEnumNumbers == p.UseEnumNumbers &&
+		o.EmitUnpopulated == p.EmitUnpopulated
+}
+
+// WithJSONOptions sets the JSON options used for every tool call and
+// resource read. ToolHandler.JSONOptions overrides them per tool.
+//
+// UseProtoNames, UseEnumNumbers and EmitUnpopulated change the shape of
+// results. A tool's OutputSchema is taken to describe results encoded with
+// the tool's own JSONOptions and the protojson defaults for the flags it
+// leaves unset; tools/list leaves it out when the options in effect differ,
+// so clients do not reject structured results that break it.
+func WithJSONOptions(opts JSONOptions) Option {
+	return func(mux *MCPServeMux) {
+		mux.jsonOptions = opts
+	}
+}
 
-var globalPreprocessor ArgPreprocessor
+type jsonOptionsKey struct{}
 
-// SetArgPreprocessor sets a global argument preprocessor that will be called
-// before decoding arguments in all DecodeArgs calls.
-func SetArgPreprocessor(fn ArgPreprocessor) {
-	globalPreprocessor = fn
+func withJSONOptions(ctx context.Context, opts JSONOptions) context.Context {
+	return context.WithValue(ctx, jsonOptionsKey{}, opts)
 }
 
-// DecodeArgs converts MCP tool arguments into a protobuf request message.
+// JSONOptionsFromContext returns the JSON options in effect for the call
+// being handled, or the zero value outside of one.
+func JSONOptionsFromContext(ctx context.Context) JSONOptions {
+	opts, _ := ctx.Value(jsonOptionsKey{}).(JSONOptions)
+	return opts
+}
+
+// DecodeArgs converts MCP tool arguments into a protobuf request message
+// with the default JSON options. It applies the SetArgPreprocessor hook
+// first, as it always has; inside a tool handler, where the mux has already
+// run it, use DecodeArgsContext instead. Decoding failures are reported as an
+// *ArgumentError locating each offending value.
 func DecodeArgs(args map[string]any, msg proto.Message) error {
+	return decodeArgs(JSONOptions{}, preprocessArgs(args), msg)
+}
+
+// DecodeArgsContext is like DecodeArgs but uses the JSON options of the
+// call in ctx. Within a tool call or resource read the mux's transformer
+// chain, global preprocessor included, has already run, so it is not
+// applied again.
+func DecodeArgsContext(ctx context.Context, args map[string]any, msg proto.Message) error {
+	opts, inCall := ctx.Value(jsonOptionsKey{}).(JSONOptions)
+	if !inCall {
+		args = preprocessArgs(args)
+	}
+	return decodeArgs(opts, args, msg)
+}
+
+func decodeArgs(opts JSONOptions, args map[string]any, msg proto.Message) error {
 	if msg == nil {
 		return nil
 	}
@@ -28,29 +131,41 @@ func DecodeArgs(args map[string]any, msg proto.Message) error {
 		args = map[string]any{}
 	}
 
-	// Apply global preprocessor if set
-	if globalPreprocessor != nil {
-		args = globalPreprocessor(args)
-	}
-
 	b, err := json.Marshal(args)
 	if err != nil {
 		return err
 	}
 	unmarshal := protojson.UnmarshalOptions{
-		DiscardUnknown: false,
+		DiscardUnknown: opts.DiscardUnknown,
+		Resolver:       opts.Resolver,
 	}
-	return unmarshal.Unmarshal(b, msg)
+	if err := unmarshal.Unmarshal(b, msg); err != nil {
+		return decodeError(opts, args, msg.ProtoReflect().Descriptor(), err)
+	}
+	return nil
 }
 
-// EncodeProto converts a protobuf response message into a JSON-compatible map.
+// EncodeProto converts a protobuf response message into a JSON-compatible map
+// with the default JSON options.
 func EncodeProto(msg proto.Message) (map[string]any, error) {
+	return encodeProto(JSONOptions{}, msg)
+}
+
+// EncodeProtoContext is like EncodeProto but uses the JSON options of the
+// call in ctx.
+func EncodeProtoContext(ctx context.Context, msg proto.Message) (map[string]any, error) {
+	return encodeProto(JSONOptionsFromContext(ctx), msg)
+}
+
+func encodeProto(opts JSONOptions, msg proto.Message) (map[string]any, error) {
 	if msg == nil {
 		return map[string]any{}, nil
 	}
 	marshal := protojson.MarshalOptions{
-		UseProtoNames:   false,
-		EmitUnpopulated: false,
+		UseProtoNames:   opts.UseProtoNames,
+		UseEnumNumbers:  opts.UseEnumNumbers,
+		EmitUnpopulated: opts.EmitUnpopulated,
+		Resolver:        opts.Resolver,
 	}
 	b, err := marshal.Marshal(msg)
 	if err != nil {
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestJSONOptions(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithJSONOptions(JSONOptions{UseProtoNames: true}))
+	mux.RegisterTool(&ToolHandler{
+		Name: "field",
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"json_name": map[string]any{"type": "string"},
+				"kind":      map[string]any{"type": "string"},
+			},
+		},
+		JSONOptions: &ToolJSONOptions{EmitUnpopulated: proto.Bool(true), UseEnumNumbers: proto.Bool(true), DiscardUnknown: proto.Bool(true)},
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			field := &typepb.Field{}
+			if err := DecodeArgsContext(ctx, args, field); err != nil {
+				return nil, err
+			}
+			return EncodeProtoContext(ctx, field)
+		},
+	})
+
+	args := map[string]any{"json_name": "x", "kind": "TYPE_STRING", "extra": true}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "field", "arguments": args}), latestVersion)
+	var resp struct {
+		Result struct {
+			IsError           bool           `json:"isError"`
+			StructuredContent map[string]any `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	got := resp.Result.StructuredContent
+	if resp.Result.IsError || got["json_name"] != "x" || got["kind"] != float64(typepb.Field_TYPE_STRING) || got["packed"] != false {
+		t.Fatalf("unexpected result: %s", rec.Body.String())
+	}
+
+	outputSchema := map[string]any{"type": "object", "additionalProperties": false, "properties": map[string]any{"jsonName": map[string]any{"type": "string"}}}
+	mux.RegisterTool(&ToolHandler{Name: "camel", OutputSchema: outputSchema})
+	mux.RegisterTool(&ToolHandler{Name: "snake", OutputSchema: outputSchema, JSONOptions: &ToolJSONOptions{UseProtoNames: proto.Bool(true)}})
+	mux.RegisterTool(&ToolHandler{
+		Name:         "pinned",
+		OutputSchema: outputSchema,
+		JSONOptions:  &ToolJSONOptions{UseProtoNames: proto.Bool(false)},
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return EncodeProtoContext(ctx, &typepb.Field{JsonName: "x"})
+		},
+	})
+	rec = postJSON(t, mux, rpc(2, "tools/call", map[string]any{"name": "pinned"}), latestVersion)
+	resp.Result.StructuredContent = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got := resp.Result.StructuredContent; got["jsonName"] != "x" {
+		t.Fatalf("tool did not turn off UseProtoNames: %s", rec.Body.String())
+	}
+	rec = postJSON(t, mux, rpc(3, "tools/list", nil), latestVersion)
+	var list struct {
+		Result struct {
+			Tools []struct {
+				Name         string         `json:"name"`
+				OutputSchema map[string]any `json:"outputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode tools/list: %v", err)
+	}
+	for _, tool := range list.Result.Tools {
+		if hasSchema := tool.OutputSchema != nil; hasSchema != (tool.Name == "snake" || tool.Name == "pinned") {
+			t.Errorf("%s: outputSchema advertised = %v", tool.Name, hasSchema)
+		}
+	}
+
+	out, err := EncodeProto(&typepb.Field{JsonName: "x"})
+	if err != nil || len(out) != 1 || out["jsonName"] != "x" {
+		t.Fatalf("EncodeProto defaults changed: %v, %v", out, err)
+	}
+}
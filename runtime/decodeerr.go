@@ -0,0 +1,311 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// decodeError rebuilds a protojson failure as violations found by walking
+// args against desc. protojson reports positions in the JSON the gateway
+// re-encoded, which mean nothing to the client.
+func decodeError(opts JSONOptions, args map[string]any, desc protoreflect.MessageDescriptor, err error) error {
+	c := decodeChecker{discardUnknown: opts.DiscardUnknown}
+	c.message(args, desc, "")
+	if len(c.violations) == 0 {
+		c.violations = []ArgumentViolation{{Message: protojsonMessage(err)}}
+	}
+	return &ArgumentError{Violations: c.violations}
+}
+
+// protojsonPrefix matches the "proto: (line 1:27): " prefix of protojson
+// errors, which randomly use a non-breaking space.
+var protojsonPrefix = regexp.MustCompile(`^proto:[\s\x{a0}]*(\(line \d+:\d+\):[\s\x{a0}]*)?`)
+
+func protojsonMessage(err error) string {
+	return protojsonPrefix.ReplaceAllString(err.Error(), "")
+}
+
+// ItemArgumentError places the violations of an error from decoding item i
+// of a client-streaming tool call under /items/i. Other errors are returned
+// unchanged.
+func ItemArgumentError(i int, err error) error {
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) {
+		return err
+	}
+	prefix := "/items/" + strconv.Itoa(i)
+	violations := make([]ArgumentViolation, len(argErr.Violations))
+	for j, v := range argErr.Violations {
+		v.Pointer = prefix + v.Pointer
+		v.Path = fieldPath(v.Pointer)
+		violations[j] = v
+	}
+	return &ArgumentError{Violations: violations}
+}
+
+// decodeChecker mirrors the checks protojson makes while decoding.
+type decodeChecker struct {
+	violations     []ArgumentViolation
+	discardUnknown bool
+}
+
+// mismatch reports a value of the wrong type or form. hint, if any, is
+// appended to the message.
+func (c *decodeChecker) mismatch(pointer, expected, hint string, got any) {
+	c.violations = append(c.violations, ArgumentViolation{
+		Pointer:  pointer,
+		Path:     fieldPath(pointer),
+		Message:  "must be " + describeTypes([]string{expected}) + hint,
+		Expected: expected,
+		Got:      describeJSON(got),
+	})
+}
+
+func (c *decodeChecker) add(pointer, message string) {
+	c.violations = append(c.violations, ArgumentViolation{Pointer: pointer, Path: fieldPath(pointer), Message: message})
+}
+
+func (c *decodeChecker) message(obj map[string]any, desc protoreflect.MessageDescriptor, pointer string) {
+	oneofs := map[protoreflect.FullName]string{}
+	for _, key := range sortedKeys(obj) {
+		child := pointer + "/" + escapePointer(key)
+		field := desc.Fields().ByJSONName(key)
+		if field == nil {
+			field = desc.Fields().ByName(protoreflect.Name(key))
+		}
+		if field == nil {
+			if !c.discardUnknown {
+				c.add(child, "is not a known field")
+			}
+			continue
+		}
+		value := obj[key]
+		if value == nil {
+			continue
+		}
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			if other, ok := oneofs[oneof.FullName()]; ok {
+				c.add(child, fmt.Sprintf("cannot be set together with %q", other))
+				continue
+			}
+			oneofs[oneof.FullName()] = key
+		}
+		c.field(value, field, child)
+	}
+}
+
+func (c *decodeChecker) field(value any, field protoreflect.FieldDescriptor, pointer string) {
+	switch {
+	case field.IsList():
+		list, ok := value.([]any)
+		if !ok {
+			c.mismatch(pointer, "array", "", value)
+			return
+		}
+		for i, item := range list {
+			c.element(item, field, pointer+"/"+strconv.Itoa(i))
+		}
+	case field.IsMap():
+		obj, ok := value.(map[string]any)
+		if !ok {
+			c.mismatch(pointer, "object", "", value)
+			return
+		}
+		for _, key := range sortedKeys(obj) {
+			child := pointer + "/" + escapePointer(key)
+			if !validMapKey(key, field.MapKey().Kind()) {
+				c.add(child, "has a key that is not "+describeTypes([]string{kindType(field.MapKey().Kind())}))
+				continue
+			}
+			c.element(obj[key], field.MapValue(), child)
+		}
+	default:
+		c.single(value, field, pointer)
+	}
+}
+
+// element checks a list element or map value, which may only be null for
+// google.protobuf.Value.
+func (c *decodeChecker) element(value any, field protoreflect.FieldDescriptor, pointer string) {
+	if value == nil {
+		if field.Message() == nil || field.Message().FullName() != "google.protobuf.Value" {
+			c.mismatch(pointer, kindType(field.Kind()), "", value)
+		}
+		return
+	}
+	c.single(value, field, pointer)
+}
+
+func (c *decodeChecker) single(value any, field protoreflect.FieldDescriptor, pointer string) {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		c.enum(value, field.Enum(), pointer)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		c.messageValue(value, field.Message(), pointer)
+	default:
+		c.scalar(value, field.Kind(), pointer)
+	}
+}
+
+// scalar checks a scalar with the validator's checks, so that both report
+// the same Expected type for a field.
+func (c *decodeChecker) scalar(value any, kind protoreflect.Kind, pointer string) {
+	expected := kindType(kind)
+	switch {
+	case !matchesType(expected, value):
+		c.mismatch(pointer, expected, "", value)
+	case !matchesKind(value, kind):
+		c.mismatch(pointer, expected, kindHint(kind), value)
+	}
+}
+
+func (c *decodeChecker) enum(value any, enum protoreflect.EnumDescriptor, pointer string) {
+	switch val := value.(type) {
+	case string:
+		if enum.Values().ByName(protoreflect.Name(val)) != nil {
+			return
+		}
+	case float64:
+		if val == math.Trunc(val) && val >= math.MinInt32 && val <= math.MaxInt32 &&
+			(!enum.IsClosed() || enum.Values().ByNumber(protoreflect.EnumNumber(val)) != nil) {
+			return
+		}
+	}
+	allowed := enumNames(enum)
+	quoted := make([]any, len(allowed))
+	for i, name := range allowed {
+		quoted[i] = name
+	}
+	c.violations = append(c.violations, ArgumentViolation{
+		Pointer:       pointer,
+		Path:          fieldPath(pointer),
+		Message:       "must be one of " + describeValues(quoted),
+		Expected:      kindType(protoreflect.EnumKind),
+		Got:           describeJSON(value),
+		AllowedValues: allowed,
+	})
+}
+
+// messageValue checks a message, including the well-known types protojson
+// encodes specially.
+func (c *decodeChecker) messageValue(value any, desc protoreflect.MessageDescriptor, pointer string) {
+	switch desc.FullName() {
+	case "google.protobuf.Value":
+		return
+	case "google.protobuf.Struct":
+		if _, ok := value.(map[string]any); !ok {
+			c.mismatch(pointer, "object", "", value)
+		}
+		return
+	case "google.protobuf.ListValue":
+		if _, ok := value.([]any); !ok {
+			c.mismatch(pointer, "array", "", value)
+		}
+		return
+	case "google.protobuf.Timestamp":
+		s, ok := value.(string)
+		if _, err := time.Parse(time.RFC3339Nano, s); !ok || err != nil {
+			c.mismatch(pointer, "string", ` in RFC 3339 format, such as "2006-01-02T15:04:05Z"`, value)
+		}
+		return
+	case "google.protobuf.Duration":
+		s, ok := value.(string)
+		if !ok || !strings.HasSuffix(s, "s") || !matchesKind(strings.TrimSuffix(s, "s"), protoreflect.DoubleKind) {
+			c.mismatch(pointer, "string", ` in seconds, such as "1.5s"`, value)
+		}
+		return
+	case "google.protobuf.FieldMask":
+		if _, ok := value.(string); !ok {
+			c.mismatch(pointer, "string", ` of comma-separated field paths`, value)
+		}
+		return
+	case "google.protobuf.Any":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			c.mismatch(pointer, "object", "", value)
+			return
+		}
+		if _, ok := obj["@type"].(string); !ok {
+			c.add(pointer+"/@type", "is required")
+		}
+		return
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		c.single(value, desc.Fields().ByName("value"), pointer)
+		return
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		c.mismatch(pointer, "object", "", value)
+		return
+	}
+	c.message(obj, desc, pointer)
+}
+
+// kindHint qualifies the type of kind in messages about values of the right
+// JSON type but the wrong form, e.g. " in the int32 range".
+func kindHint(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BytesKind:
+		return " holding base64 data"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return " in the int32 range"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return " in the uint32 range"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return " in the int64 range"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return " in the uint64 range"
+	}
+	return ""
+}
+
+func validMapKey(key string, kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.StringKind:
+		return true
+	case protoreflect.BoolKind:
+		return key == "true" || key == "false"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		_, err := strconv.ParseInt(key, 10, 32)
+		return err == nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		_, err := strconv.ParseInt(key, 10, 64)
+		return err == nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		_, err := strconv.ParseUint(key, 10, 32)
+		return err == nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		_, err := strconv.ParseUint(key, 10, 64)
+		return err == nil
+	}
+	return false
+}
+
+// enumNames returns the names of the values a client may pick, leaving out
+// the zero-value sentinel unless it is the only value, as the generated
+// schemas do.
+func enumNames(enum protoreflect.EnumDescriptor) []string {
+	values := enum.Values()
+	var names []string
+	for i := 0; i < values.Len(); i++ {
+		if values.Get(i).Number() != 0 {
+			names = append(names, string(values.Get(i).Name()))
+		}
+	}
+	if len(names) == 0 {
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name:            "define",
+		InputDescriptor: (&typepb.Type{}).ProtoReflect().Descriptor(),
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			if err := DecodeArgsContext(ctx, args, &typepb.Type{}); err != nil {
+				return nil, err
+			}
+			return "ok", nil
+		},
+	})
+
+	args := map[string]any{
+		"syntax":        "proto4",
+		"fields":        []any{map[string]any{"number": "ten", "packed": true}},
+		"oneofs":        "choice",
+		"sourceContext": 5,
+		"bogus":         1,
+	}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "define", "arguments": args}), latestVersion)
+	var resp struct {
+		Result struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			StructuredContent struct {
+				Error struct {
+					Code       string              `json:"code"`
+					Violations []ArgumentViolation `json:"violations"`
+				} `json:"error"`
+			} `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := strings.Join([]string{
+		"INVALID_ARGUMENT: invalid arguments",
+		"- /bogus: is not a known field",
+		"- /fields/0/number: must be an integer",
+		"- /oneofs: must be an array",
+		"- /sourceContext: must be an object",
+		`- /syntax: must be one of "SYNTAX_PROTO3", "SYNTAX_EDITIONS"`,
+	}, "\n")
+	if !resp.Result.IsError || resp.Result.Content[0].Text != want {
+		t.Fatalf("got %s, want:\n%s", rec.Body.String(), want)
+	}
+	violations := resp.Result.StructuredContent.Error.Violations
+	number, syntax := violations[1], violations[4]
+	if number.Path != "fields[0].number" || number.Expected != "integer" || number.Got != `string "ten"` {
+		t.Errorf("number violation: %+v", number)
+	}
+	if syntax.Expected != "string" || syntax.Got != `string "proto4"` || len(syntax.AllowedValues) != 2 {
+		t.Errorf("syntax violation: %+v", syntax)
+	}
+
+	// The validator reports the same value in the same terms.
+	schema := map[string]any{"type": "object", "properties": map[string]any{"number": map[string]any{"type": "integer", "format": "int32"}}}
+	validated := ValidateArgs(schema, map[string]any{"number": "ten"})
+	if len(validated) != 1 || validated[0].Expected != number.Expected || validated[0].Message != number.Message {
+		t.Errorf("validator and decoder disagree: %+v vs %+v", validated, number)
+	}
+	decoded := DecodeArgs(map[string]any{"number": 3e10}, &typepb.Field{})
+	if !errors.As(decoded, new(*ArgumentError)) || !strings.Contains(decoded.Error(), "/number: must be an integer in the int32 range") {
+		t.Errorf("out-of-range int32: %v", decoded)
+	}
+
+	err := ItemArgumentError(3, DecodeArgs(map[string]any{"name": 7}, &typepb.Type{}))
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) || argErr.Violations[0].Pointer != "/items/3/name" || argErr.Violations[0].Path != "items[3].name" {
+		t.Errorf("ItemArgumentError: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// errorDetails holds the google.rpc details of a status that are reported
+// to the client.
+type errorDetails struct {
+	violations []map[string]interface{}
+	reason     *errdetails.ErrorInfo
+	retryDelay string
+}
+
+// unpackErrorDetails collects BadRequest, ErrorInfo and RetryInfo details.
+// Field violation paths are translated to the JSON names of input when it is
+// known, unless protoNames is set.
+func unpackErrorDetails(st *status.Status, input protoreflect.MessageDescriptor, protoNames bool) errorDetails {
+	var out errorDetails
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				violation := map[string]interface{}{
+					"field":       jsonFieldPath(v.GetField(), input, protoNames),
+					"description": v.GetDescription(),
+				}
+				if v.GetReason() != "" {
+					violation["reason"] = v.GetReason()
+				}
+				out.violations = append(out.violations, violation)
+			}
+		case *errdetails.ErrorInfo:
+			out.reason = d
+		case *errdetails.RetryInfo:
+			if d.GetRetryDelay() != nil {
+				out.retryDelay = d.GetRetryDelay().AsDuration().String()
+			}
+		}
+	}
+	return out
+}
+
+// addTo adds the details to the structured error and its text lines.
+func (d errorDetails) addTo(structured map[string]interface{}, lines []string) []string {
+	if len(d.violations) > 0 {
+		structured["fieldViolations"] = d.violations
+		for _, v := range d.violations {
+			lines = append(lines, fmt.Sprintf("- %s: %s", v["field"], v["description"]))
+		}
+	}
+	if d.reason != nil {
+		structured["reason"] = d.reason.GetReason()
+		if d.reason.GetDomain() != "" {
+			structured["domain"] = d.reason.GetDomain()
+		}
+		if len(d.reason.GetMetadata()) > 0 {
+			structured["metadata"] = d.reason.GetMetadata()
+		}
+		lines = append(lines, "Reason: "+d.reason.GetReason())
+	}
+	if d.retryDelay != "" {
+		structured["retryDelay"] = d.retryDelay
+		lines = append(lines, "Retry after "+d.retryDelay+".")
+	}
+	return lines
+}
+
+// jsonFieldPath translates a proto field path such as
+// "email_addresses[1].email" into the JSON names clients send, e.g.
+// "emailAddresses[1].email". Without a descriptor, or past a segment it
+// cannot resolve, names are converted to lowerCamelCase. With protoNames the
+// proto names are kept instead, matching JSONOptions.UseProtoNames.
+func jsonFieldPath(path string, msg protoreflect.MessageDescriptor, protoNames bool) string {
+	if path == "" {
+		return path
+	}
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		name, suffix := segment, ""
+		if j := strings.IndexByte(segment, '['); j >= 0 {
+			name, suffix = segment[:j], segment[j:]
+		}
+
+		var field protoreflect.FieldDescriptor
+		if msg != nil {
+			field = msg.Fields().ByName(protoreflect.Name(name))
+			if field == nil {
+				field = msg.Fields().ByJSONName(name)
+			}
+		}
+		if field == nil {
+			if !protoNames {
+				name = lowerCamelCase(name)
+			}
+			segments[i] = name + suffix
+			msg = nil
+			continue
+		}
+		if protoNames {
+			segments[i] = string(field.Name()) + suffix
+		} else {
+			segments[i] = field.JSONName() + suffix
+		}
+
+		msg = nil
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			msg = field.Message()
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// lowerCamelCase converts a proto field name the way protoc derives its
+// default JSON name.
+func lowerCamelCase(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
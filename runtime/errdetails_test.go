@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestToolErrorDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid message").WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "nested_type[0].field[1].json_name", Description: "must be set"},
+			{Field: "extra_field", Description: "unknown"},
+		}},
+		&errdetails.ErrorInfo{Reason: "NAME_TAKEN", Domain: "example.com"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+	if err != nil {
+		t.Fatalf("WithDetails: %v", err)
+	}
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name:            "define",
+		InputDescriptor: (&descriptorpb.DescriptorProto{}).ProtoReflect().Descriptor(),
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return nil, st.Err()
+		},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "define"}), latestVersion)
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			StructuredContent struct {
+				Error json.RawMessage `json:"error"`
+			} `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	wantText := strings.Join([]string{
+		"INVALID_ARGUMENT: invalid message (retryable)",
+		"- nestedType[0].field[1].jsonName: must be set",
+		"- extraField: unknown",
+		"Reason: NAME_TAKEN",
+		"Retry after 1.5s.",
+	}, "\n")
+	if got := resp.Result.Content[0].Text; got != wantText {
+		t.Errorf("text: got %q, want %q", got, wantText)
+	}
+	wantError := `{"code":"INVALID_ARGUMENT","domain":"example.com","fieldViolations":[{"description":"must be set","field":"nestedType[0].field[1].jsonName"},{"description":"unknown","field":"extraField"}],"message":"invalid message","reason":"NAME_TAKEN","retryDelay":"1.5s","retryable":true}`
+	if got := string(resp.Result.StructuredContent.Error); got != wantError {
+		t.Errorf("structured error: got %s, want %s", got, wantError)
+	}
+}
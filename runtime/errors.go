@@ -0,0 +1,123 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ToolErrorMapping describes how a gRPC status code is reported to the
+// client when a tool call fails.
+type ToolErrorMapping struct {
+	// Name is the code name shown to the client, e.g. "NOT_FOUND".
+	Name string
+	// Retryable hints that the same call may succeed if retried later.
+	Retryable bool
+}
+
+// DefaultToolErrorMappings is the mapping used by muxes that do not override
+// it with WithToolErrorMappings.
+var DefaultToolErrorMappings = map[codes.Code]ToolErrorMapping{
+	codes.OK:                 {Name: "OK"},
+	codes.Canceled:           {Name: "CANCELLED"},
+	codes.Unknown:            {Name: "UNKNOWN"},
+	codes.InvalidArgument:    {Name: "INVALID_ARGUMENT"},
+	codes.DeadlineExceeded:   {Name: "DEADLINE_EXCEEDED", Retryable: true},
+	codes.NotFound:           {Name: "NOT_FOUND"},
+	codes.AlreadyExists:      {Name: "ALREADY_EXISTS"},
+	codes.PermissionDenied:   {Name: "PERMISSION_DENIED"},
+	codes.ResourceExhausted:  {Name: "RESOURCE_EXHAUSTED", Retryable: true},
+	codes.FailedPrecondition: {Name: "FAILED_PRECONDITION"},
+	codes.Aborted:            {Name: "ABORTED", Retryable: true},
+	codes.OutOfRange:         {Name: "OUT_OF_RANGE"},
+	codes.Unimplemented:      {Name: "UNIMPLEMENTED"},
+	codes.Internal:           {Name: "INTERNAL"},
+	codes.Unavailable:        {Name: "UNAVAILABLE", Retryable: true},
+	codes.DataLoss:           {Name: "DATA_LOSS"},
+	codes.Unauthenticated:    {Name: "UNAUTHENTICATED"},
+}
+
+// WithToolErrorMappings overrides how individual gRPC codes are reported in
+// tool error results. Codes not listed keep their default mapping.
+func WithToolErrorMappings(mappings map[codes.Code]ToolErrorMapping) Option {
+	return func(mux *MCPServeMux) {
+		for code, mapping := range mappings {
+			mux.toolErrorMappings[code] = mapping
+		}
+	}
+}
+
+// Error lets handlers return a JSON-RPC error instead of a tool error result.
+func (e *MCPError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// toolErrorStatus converts a handler error into a gRPC status. Errors that
+// do not carry a status are reported as UNKNOWN, context errors as
+// CANCELLED or DEADLINE_EXCEEDED and argument errors as INVALID_ARGUMENT.
+func toolErrorStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		return status.New(codes.InvalidArgument, "invalid arguments")
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		st := status.FromContextError(err)
+		return status.New(st.Code(), err.Error())
+	}
+	return status.New(codes.Unknown, err.Error())
+}
+
+// toolErrorResult builds the isError result reported for a failed tool call.
+// input describes the tool's request message and may be nil.
+func (mux *MCPServeMux) toolErrorResult(ctx context.Context, err error, input protoreflect.MessageDescriptor) map[string]interface{} {
+	st := toolErrorStatus(err)
+	mapping, ok := mux.toolErrorMappings[st.Code()]
+	if !ok {
+		mapping = ToolErrorMapping{Name: st.Code().String()}
+	}
+	details := unpackErrorDetails(st, input, JSONOptionsFromContext(ctx).UseProtoNames)
+	// A server asking for a delay before retrying expects a retry.
+	retryable := mapping.Retryable || details.retryDelay != ""
+
+	text := mapping.Name + ": " + st.Message()
+	if retryable {
+		text += " (retryable)"
+	}
+	structured := map[string]interface{}{
+		"code":      mapping.Name,
+		"message":   st.Message(),
+		"retryable": retryable,
+	}
+	lines := details.addTo(structured, []string{text})
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		structured["violations"] = argErr.Violations
+		for _, v := range argErr.Violations {
+			lines = append(lines, "- "+violationLabel(v.Pointer)+": "+v.Message)
+		}
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": strings.Join(lines, "\n"),
+			},
+		},
+		"isError": true,
+	}
+	if supportsStructuredContent(ProtocolVersionFromContext(ctx)) {
+		result["structuredContent"] = map[string]interface{}{
+			"error": structured,
+		}
+	}
+	return result
+}
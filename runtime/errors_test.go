@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToolErrorResults(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithToolErrorMappings(map[codes.Code]ToolErrorMapping{
+		codes.FailedPrecondition: {Name: "FAILED_PRECONDITION", Retryable: true},
+	}))
+	errs := map[string]error{
+		"missing":  status.Error(codes.NotFound, "task 42 not found"),
+		"busy":     status.Error(codes.Unavailable, "backend down"),
+		"locked":   status.Error(codes.FailedPrecondition, "task is locked"),
+		"plain":    fmt.Errorf("boom"),
+		"protocol": &MCPError{Code: -32602, Message: "bad params"},
+	}
+	for name, err := range errs {
+		mux.RegisterTool(&ToolHandler{
+			Name:    name,
+			Handler: func(ctx context.Context, args map[string]any) (any, error) { return nil, err },
+		})
+	}
+
+	tests := []struct {
+		tool string
+		want string
+	}{
+		{"missing", `{"content":[{"text":"NOT_FOUND: task 42 not found","type":"text"}],"isError":true,"structuredContent":{"error":{"code":"NOT_FOUND","message":"task 42 not found","retryable":false}}}`},
+		{"busy", `{"content":[{"text":"UNAVAILABLE: backend down (retryable)","type":"text"}],"isError":true,"structuredContent":{"error":{"code":"UNAVAILABLE","message":"backend down","retryable":true}}}`},
+		{"locked", `{"content":[{"text":"FAILED_PRECONDITION: task is locked (retryable)","type":"text"}],"isError":true,"structuredContent":{"error":{"code":"FAILED_PRECONDITION","message":"task is locked","retryable":true}}}`},
+		{"plain", `{"content":[{"text":"UNKNOWN: boom","type":"text"}],"isError":true,"structuredContent":{"error":{"code":"UNKNOWN","message":"boom","retryable":false}}}`},
+	}
+	for _, tt := range tests {
+		rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": tt.tool}), latestVersion)
+		var resp struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode response: %v", tt.tool, err)
+		}
+		if string(resp.Result) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.tool, resp.Result, tt.want)
+		}
+	}
+
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "protocol"}), latestVersion)
+	var resp MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("protocol error: got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func newTestMux(opts ...Option) *MCPServeMux {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test", Version: "v0.0.0"}, opts...)
+	mux.RegisterTool(&ToolHandler{
+		Name: "echo",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return args, nil
+		},
+	})
+	return mux
+}
+
+func postJSON(t *testing.T, h http.Handler, body any, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// latestVersion marks a request as coming from a client that negotiated the
+// latest protocol version; without it stateless requests get the fallback.
+var latestVersion = http.Header{ProtocolVersionHeader: {LatestProtocolVersion}}
+
+func rpc(id any, method string, params map[string]any) map[string]any {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method}
+	if id != nil {
+		msg["id"] = id
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+	return msg
+}
+
+// recordingSink collects the messages sent to it.
+type recordingSink struct {
+	mu   sync.Mutex
+	msgs []any
+}
+
+func (s *recordingSink) send(msg any) error {
+	s.mu.Lock()
+	s.msgs = append(s.msgs, msg)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+)
+
+// LogLevel is an MCP log severity, ordered as in RFC 5424.
+type LogLevel string
+
+const (
+	LogLevelDebug     LogLevel = "debug"
+	LogLevelInfo      LogLevel = "info"
+	LogLevelNotice    LogLevel = "notice"
+	LogLevelWarning   LogLevel = "warning"
+	LogLevelError     LogLevel = "error"
+	LogLevelCritical  LogLevel = "critical"
+	LogLevelAlert     LogLevel = "alert"
+	LogLevelEmergency LogLevel = "emergency"
+)
+
+var logLevelSeverity = map[LogLevel]int{
+	LogLevelDebug:     0,
+	LogLevelInfo:      1,
+	LogLevelNotice:    2,
+	LogLevelWarning:   3,
+	LogLevelError:     4,
+	LogLevelCritical:  5,
+	LogLevelAlert:     6,
+	LogLevelEmergency: 7,
+}
+
+// WithDefaultLogLevel sets the minimum level of messages sent to clients that
+// have not called logging/setLevel. The default is LogLevelInfo.
+func WithDefaultLogLevel(level LogLevel) Option {
+	return func(mux *MCPServeMux) {
+		if _, ok := logLevelSeverity[level]; ok {
+			mux.defaultLogLevel = level
+		}
+	}
+}
+
+// Logger sends log messages to the MCP client as notifications/message.
+// Messages go out on the stream of the request that produced them, or the
+// session's server-initiated stream, and are dropped when neither exists.
+type Logger struct {
+	ctx   context.Context
+	name  string
+	level LogLevel
+}
+
+type toolNameKey struct{}
+
+type defaultLogLevelKey struct{}
+
+func withToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+// ToolNameFromContext returns the name of the tool being called, if any.
+func ToolNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(toolNameKey{}).(string)
+	return name, ok
+}
+
+// LoggerFromContext returns a logger for the request in ctx. Inside a tool
+// handler the logger is named after the tool.
+func LoggerFromContext(ctx context.Context) *Logger {
+	name, _ := ToolNameFromContext(ctx)
+	level, _ := ctx.Value(defaultLogLevelKey{}).(LogLevel)
+	if sess := sessionFromContext(ctx); sess != nil {
+		if l := sess.getLogLevel(); l != "" {
+			level = l
+		}
+	}
+	if level == "" {
+		level = LogLevelInfo
+	}
+	return &Logger{ctx: ctx, name: name, level: level}
+}
+
+// Named returns a copy of the logger reporting under the given name.
+func (l *Logger) Named(name string) *Logger {
+	out := *l
+	out.name = name
+	return &out
+}
+
+// Enabled reports whether messages at level reach the client.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return logLevelSeverity[level] >= logLevelSeverity[l.level]
+}
+
+// Log sends data at the given level. Data may be a string or any
+// JSON-serializable value.
+func (l *Logger) Log(level LogLevel, data any) {
+	if !l.Enabled(level) {
+		return
+	}
+	params := map[string]interface{}{
+		"level": level,
+		"data":  data,
+	}
+	if l.name != "" {
+		params["logger"] = l.name
+	}
+	_ = notify(l.ctx, "notifications/message", params)
+}
+
+// Debugf logs a formatted message at LogLevelDebug.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.Log(LogLevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted message at LogLevelInfo.
+func (l *Logger) Infof(format string, args ...any) {
+	l.Log(LogLevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warningf logs a formatted message at LogLevelWarning.
+func (l *Logger) Warningf(format string, args ...any) {
+	l.Log(LogLevelWarning, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted message at LogLevelError.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.Log(LogLevelError, fmt.Sprintf(format, args...))
+}
+
+func (mux *MCPServeMux) handleSetLogLevel(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	level, _ := params["level"].(string)
+	if _, ok := logLevelSeverity[LogLevel(level)]; !ok {
+		return nil, &MCPError{Code: -32602, Message: fmt.Sprintf("Invalid log level: %s", level)}
+	}
+	// Stateless requests have no session to remember the level in.
+	if sess := sessionFromContext(ctx); sess != nil {
+		sess.setLogLevel(LogLevel(level))
+	}
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoggingNotifications(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "partial",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			logger := LoggerFromContext(ctx)
+			logger.Debugf("skipped shard %d", 1)
+			logger.Warningf("shard %d unavailable", 2)
+			return "ok", nil
+		},
+	})
+
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"warning"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"partial"}}`,
+	}, "\n"))
+	var out bytes.Buffer
+	if err := mux.ServeConn(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d messages, want 3:\n%s", len(lines), out.String())
+	}
+	want := `{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"shard 2 unavailable","level":"warning","logger":"partial"}}`
+	if lines[1] != want {
+		t.Fatalf("got %s, want %s", lines[1], want)
+	}
+}
+
+func TestLoggingCapability(t *testing.T) {
+	rec := postJSON(t, newTestMux(), rpc(1, "initialize", nil), nil)
+	if strings.Contains(rec.Body.String(), `"logging"`) {
+		t.Fatalf("stateless mux advertised logging: %s", rec.Body.String())
+	}
+
+	var out bytes.Buffer
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n")
+	if err := newTestMux().ServeConn(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+	if !strings.Contains(out.String(), `"logging":{}`) {
+		t.Fatalf("stdio connection not advertised logging: %s", out.String())
+	}
+}
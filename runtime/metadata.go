@@ -0,0 +1,257 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataHeaderPrefix is the HTTP header prefix forwarded to gRPC metadata
+// by DefaultHeaderMatcher, with the prefix removed.
+const MetadataHeaderPrefix = "Grpc-Metadata-"
+
+// Metadata keys attached to every tool call unless WithoutDefaultMetadata is
+// used.
+const (
+	MetadataSessionID = "mcp-session-id"
+	MetadataRequestID = "mcp-request-id"
+	MetadataToolName  = "mcp-tool-name"
+)
+
+// HeaderMatcherFunc decides whether an HTTP header is forwarded as gRPC
+// metadata and under which key.
+type HeaderMatcherFunc func(key string) (string, bool)
+
+// DefaultHeaderMatcher forwards Authorization, as grpc-gateway does, and
+// headers prefixed with MetadataHeaderPrefix.
+func DefaultHeaderMatcher(key string) (string, bool) {
+	key = http.CanonicalHeaderKey(key)
+	if key == "Authorization" {
+		return "authorization", true
+	}
+	if strings.HasPrefix(key, MetadataHeaderPrefix) {
+		return key[len(MetadataHeaderPrefix):], true
+	}
+	return "", false
+}
+
+// WithIncomingHeaderMatcher sets the matcher that selects which HTTP request
+// headers are sent to the backend as gRPC metadata. It replaces
+// DefaultHeaderMatcher.
+func WithIncomingHeaderMatcher(fn HeaderMatcherFunc) Option {
+	return func(mux *MCPServeMux) {
+		if fn != nil {
+			mux.incomingHeaderMatcher = fn
+		}
+	}
+}
+
+// WithoutDefaultMetadata stops the mux from attaching the session ID,
+// JSON-RPC request ID and tool name to outgoing gRPC metadata.
+func WithoutDefaultMetadata() Option {
+	return func(mux *MCPServeMux) {
+		mux.defaultMetadata = false
+	}
+}
+
+type incomingMetadataKey struct{}
+
+type requestIDKey struct{}
+
+// withIncomingHeaders stores the HTTP headers selected by the mux's matcher.
+func (mux *MCPServeMux) withIncomingHeaders(ctx context.Context, header http.Header) context.Context {
+	md := metadata.MD{}
+	for key, values := range header {
+		if name, ok := mux.incomingHeaderMatcher(key); ok {
+			md.Append(name, values...)
+		}
+	}
+	if md.Len() == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, incomingMetadataKey{}, md)
+}
+
+func withRequestID(ctx context.Context, id any) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the JSON-RPC ID of the request in ctx.
+func RequestIDFromContext(ctx context.Context) (any, bool) {
+	id := ctx.Value(requestIDKey{})
+	return id, id != nil
+}
+
+// outgoingMetadata attaches forwarded headers and, unless disabled, the MCP
+// session ID, request ID and tool name to the outgoing gRPC context.
+func (mux *MCPServeMux) outgoingMetadata(ctx context.Context) context.Context {
+	md := metadata.MD{}
+	if incoming, ok := ctx.Value(incomingMetadataKey{}).(metadata.MD); ok {
+		md = incoming.Copy()
+	}
+	if mux.defaultMetadata {
+		if id, ok := SessionIDFromContext(ctx); ok && id != "" {
+			md.Set(MetadataSessionID, id)
+		}
+		if id, ok := RequestIDFromContext(ctx); ok {
+			md.Set(MetadataRequestID, requestIDString(id))
+		}
+		if name, ok := ToolNameFromContext(ctx); ok {
+			md.Set(MetadataToolName, name)
+		}
+	}
+	if md.Len() == 0 {
+		return ctx
+	}
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(existing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// requestIDString formats a JSON-RPC ID for metadata: strings as is, numbers
+// as their JSON encoding.
+func requestIDString(id any) string {
+	if s, ok := id.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(id)
+	return string(b)
+}
+
+// MetadataTarget is a set of places a gRPC response header or trailer key is
+// reported to the client.
+type MetadataTarget uint8
+
+const (
+	// ResultMeta reports the key in the tool result's _meta.
+	ResultMeta MetadataTarget = 1 << iota
+	// HTTPHeader sends the key as an HTTP response header. It only applies
+	// to plain JSON responses: SSE responses send their headers before any
+	// call completes.
+	HTTPHeader
+)
+
+// OutgoingHeaderMatcherFunc decides where a gRPC response header or trailer
+// key is reported and under which name. A zero target drops the key.
+type OutgoingHeaderMatcherFunc func(key string) (string, MetadataTarget)
+
+// DefaultOutgoingHeaderMatcher reports every key in the result's _meta,
+// except content-type and reserved grpc- keys.
+func DefaultOutgoingHeaderMatcher(key string) (string, MetadataTarget) {
+	if key == "content-type" || strings.HasPrefix(key, "grpc-") {
+		return "", 0
+	}
+	return key, ResultMeta
+}
+
+// WithOutgoingHeaderMatcher sets the matcher that decides which gRPC
+// response headers and trailers reach the client. It replaces
+// DefaultOutgoingHeaderMatcher.
+func WithOutgoingHeaderMatcher(fn OutgoingHeaderMatcherFunc) Option {
+	return func(mux *MCPServeMux) {
+		if fn != nil {
+			mux.outgoingHeaderMatcher = fn
+		}
+	}
+}
+
+// responseMetadata collects the gRPC response metadata of a tool call.
+type responseMetadata struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+type responseMetadataKey struct{}
+
+func withResponseMetadata(ctx context.Context) (context.Context, *responseMetadata) {
+	md := &responseMetadata{}
+	return context.WithValue(ctx, responseMetadataKey{}, md), md
+}
+
+// ResponseMetadata returns call options that capture the header and trailer
+// of a gRPC call made by a tool handler, so the mux can report them. Outside
+// a tool call it returns nil.
+func ResponseMetadata(ctx context.Context) []grpc.CallOption {
+	md, ok := ctx.Value(responseMetadataKey{}).(*responseMetadata)
+	if !ok {
+		return nil
+	}
+	return []grpc.CallOption{grpc.Header(&md.header), grpc.Trailer(&md.trailer)}
+}
+
+// recordStreamMetadata captures the header, and the trailer once the stream
+// has ended, of a stream that was opened without ResponseMetadata.
+func recordStreamMetadata(ctx context.Context, stream any, ended bool) {
+	md, ok := ctx.Value(responseMetadataKey{}).(*responseMetadata)
+	if !ok {
+		return
+	}
+	s, ok := stream.(interface {
+		Header() (metadata.MD, error)
+		Trailer() metadata.MD
+	})
+	if !ok {
+		return
+	}
+	if header, err := s.Header(); err == nil {
+		md.header = header
+	}
+	if ended {
+		md.trailer = s.Trailer()
+	}
+}
+
+// responseHeaders collects HTTP response headers from the calls of one POST.
+type responseHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+type responseHeadersKey struct{}
+
+func withResponseHeaders(ctx context.Context) (context.Context, *responseHeaders) {
+	h := &responseHeaders{header: http.Header{}}
+	return context.WithValue(ctx, responseHeadersKey{}, h), h
+}
+
+// copyTo adds the collected headers to dst.
+func (h *responseHeaders) copyTo(dst http.Header) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, values := range h.header {
+		for _, v := range values {
+			dst.Add(key, v)
+		}
+	}
+}
+
+// applyResponseMetadata routes captured response metadata into the result's
+// _meta and the HTTP response headers, as chosen by the outgoing matcher.
+func (mux *MCPServeMux) applyResponseMetadata(ctx context.Context, md *responseMetadata, result map[string]interface{}) {
+	meta := map[string]interface{}{}
+	headers, _ := ctx.Value(responseHeadersKey{}).(*responseHeaders)
+	for _, source := range []metadata.MD{md.header, md.trailer} {
+		for key, values := range source {
+			name, target := mux.outgoingHeaderMatcher(key)
+			if target&ResultMeta != 0 {
+				meta[name] = strings.Join(values, ", ")
+			}
+			if target&HTTPHeader != 0 && headers != nil {
+				headers.mu.Lock()
+				for _, v := range values {
+					headers.header.Add(name, v)
+				}
+				headers.mu.Unlock()
+			}
+		}
+	}
+	if len(meta) > 0 {
+		result["_meta"] = meta
+	}
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOutgoingMetadata(t *testing.T) {
+	var got metadata.MD
+	handler := func(ctx context.Context, args map[string]any) (any, error) {
+		got, _ = metadata.FromOutgoingContext(ctx)
+		return "ok", nil
+	}
+	header := http.Header{
+		"Authorization":        {"Bearer token"},
+		"Grpc-Metadata-Tenant": {"acme"},
+		"X-Request-Id":         {"abc"},
+		"Mcp-Protocol-Version": {LatestProtocolVersion},
+	}
+
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{Name: "inspect", Handler: handler})
+	postJSON(t, mux, rpc("req-1", "tools/call", map[string]any{"name": "inspect"}), header)
+	want := metadata.MD{"authorization": {"Bearer token"}, "tenant": {"acme"}, "mcp-request-id": {"req-1"}, "mcp-tool-name": {"inspect"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("default matcher: got %v, want %v", got, want)
+	}
+
+	mux = NewMCPServeMux(ServerMetadata{Name: "test"}, WithoutDefaultMetadata(), WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if key == "X-Request-Id" {
+			return key, true
+		}
+		return "", false
+	}))
+	mux.RegisterTool(&ToolHandler{Name: "inspect", Handler: handler})
+	postJSON(t, mux, rpc(7, "tools/call", map[string]any{"name": "inspect"}), header)
+	want = metadata.MD{"x-request-id": {"abc"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("custom matcher: got %v, want %v", got, want)
+	}
+}
+
+func TestResponseMetadata(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithOutgoingHeaderMatcher(func(key string) (string, MetadataTarget) {
+		switch key {
+		case "x-ratelimit-remaining":
+			return "X-Ratelimit-Remaining", ResultMeta | HTTPHeader
+		case "x-request-id":
+			return "requestId", ResultMeta
+		}
+		return "", 0
+	}))
+	mux.RegisterTool(&ToolHandler{
+		Name: "fetch",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			// Stand in for the gRPC client filling in the call options.
+			opts := ResponseMetadata(ctx)
+			*opts[0].(grpc.HeaderCallOption).HeaderAddr = metadata.Pairs("x-request-id", "r-1", "content-type", "application/grpc")
+			*opts[1].(grpc.TrailerCallOption).TrailerAddr = metadata.Pairs("x-ratelimit-remaining", "41")
+			return map[string]any{"ok": true}, nil
+		},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "fetch"}), latestVersion)
+	if got := rec.Header().Get("X-Ratelimit-Remaining"); got != "41" {
+		t.Errorf("HTTP header: got %q, want 41", got)
+	}
+	var resp struct {
+		Result struct {
+			Meta map[string]any `json:"_meta"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got := fmt.Sprint(resp.Result.Meta); got != "map[X-Ratelimit-Remaining:41 requestId:r-1]" {
+		t.Errorf("_meta: got %s", got)
+	}
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// ToolOrder controls the order in which tools/list returns tools.
+type ToolOrder int
+
+const (
+	// ToolOrderRegistration lists tools in the order they were first registered.
+	ToolOrderRegistration ToolOrder = iota
+	// ToolOrderName lists tools sorted by name.
+	ToolOrderName
+)
+
+// WithToolOrder sets the order of tools in tools/list. The default is
+// ToolOrderRegistration.
+func WithToolOrder(order ToolOrder) Option {
+	return func(mux *MCPServeMux) {
+		mux.toolOrder = order
+	}
+}
+
+// WithToolsPageSize paginates tools/list with at most n tools per page,
+// returning a nextCursor while more tools remain. Zero disables pagination.
+func WithToolsPageSize(n int) Option {
+	return func(mux *MCPServeMux) {
+		if n >= 0 {
+			mux.toolsPageSize = n
+		}
+	}
+}
+
+// encodeCursor returns the opaque cursor for the page starting at offset.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor. An empty cursor
+// starts at the first page.
+func decodeCursor(cursor string) (int, bool) {
+	if cursor == "" {
+		return 0, true
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+	return offset, true
+}
+
+// paginate returns the bounds of the page starting at offset and the cursor
+// of the following page, if any.
+func paginate(total, offset, pageSize int) (start, end int, next string) {
+	start = min(offset, total)
+	end = total
+	if pageSize > 0 && start+pageSize < total {
+		end = start + pageSize
+		next = encodeCursor(end)
+	}
+	return start, end, next
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestListToolsPagination(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithToolOrder(ToolOrderName), WithToolsPageSize(2))
+	for _, name := range []string{"c", "a", "e", "b", "d"} {
+		mux.RegisterTool(&ToolHandler{Name: name})
+	}
+
+	var names []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		rec := postJSON(t, mux, rpc(page, "tools/list", params), nil)
+		var resp struct {
+			Result struct {
+				Tools []struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+				NextCursor string `json:"nextCursor"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		for _, tool := range resp.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		if cursor = resp.Result.NextCursor; cursor == "" {
+			break
+		}
+	}
+	if got := strings.Join(names, ","); got != "a,b,c,d,e" {
+		t.Fatalf("got tools %s, want a,b,c,d,e", got)
+	}
+
+	rec := postJSON(t, mux, rpc(9, "tools/list", map[string]any{"cursor": "!"}), nil)
+	if !strings.Contains(rec.Body.String(), "-32602") {
+		t.Fatalf("invalid cursor accepted: %s", rec.Body.String())
+	}
+}
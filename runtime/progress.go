@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressReporter sends notifications/progress for a request whose client
+// supplied a _meta.progressToken. Without a token every report is dropped.
+type ProgressReporter struct {
+	ctx   context.Context
+	token any
+
+	mu   sync.Mutex
+	last float64
+	sent bool
+}
+
+type progressKey struct{}
+
+func withProgressToken(ctx context.Context, token any) context.Context {
+	return context.WithValue(ctx, progressKey{}, &ProgressReporter{ctx: ctx, token: token})
+}
+
+// ProgressFromContext returns the progress reporter of the request in ctx.
+// The reporter is never nil.
+func ProgressFromContext(ctx context.Context) *ProgressReporter {
+	if p, ok := ctx.Value(progressKey{}).(*ProgressReporter); ok {
+		return p
+	}
+	return &ProgressReporter{}
+}
+
+// Enabled reports whether the client asked for progress notifications.
+func (p *ProgressReporter) Enabled() bool {
+	return p.token != nil
+}
+
+// Report sends the current progress. Total is omitted when zero, message when
+// empty. Progress must increase with every notification, so reports that do
+// not advance past the previous one are dropped.
+func (p *ProgressReporter) Report(progress, total float64, message string) {
+	if p.token == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if p.sent && progress <= p.last {
+		p.mu.Unlock()
+		return
+	}
+	p.last, p.sent = progress, true
+	p.mu.Unlock()
+
+	params := map[string]interface{}{
+		"progressToken": p.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	_ = notify(p.ctx, "notifications/progress", params)
+}
+
+// progressToken returns the _meta.progressToken of request params, if any.
+func progressToken(params map[string]interface{}) (any, bool) {
+	meta, _ := params["_meta"].(map[string]interface{})
+	token, ok := meta["progressToken"]
+	if !ok || token == nil {
+		return nil, false
+	}
+	return token, true
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProgressNotifications(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "export",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			progress := ProgressFromContext(ctx)
+			progress.Report(1, 2, "half")
+			progress.Report(1, 2, "stalled")
+			progress.Report(2, 2, "")
+			return "done", nil
+		},
+	})
+
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"export"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"export","_meta":{"progressToken":"tok"}}}`,
+	}, "\n"))
+	var out bytes.Buffer
+	if err := mux.ServeConn(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+
+	var progress []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if strings.Contains(line, "notifications/progress") {
+			progress = append(progress, line)
+		}
+	}
+	want := []string{
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"message":"half","progress":1,"progressToken":"tok","total":2}}`,
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":2,"progressToken":"tok","total":2}}`,
+	}
+	if strings.Join(progress, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got progress:\n%s\nwant:\n%s", strings.Join(progress, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Prompt is an MCP prompt template. Message texts may reference arguments as
+// {{name}}; prompts/get substitutes the values supplied by the client.
+type Prompt struct {
+	Name        string
+	Title       string
+	Description string
+	Arguments   []PromptArgument
+	Messages    []PromptMessage
+
+	// Handler, if set, renders the prompt instead of the Messages templates.
+	Handler func(ctx context.Context, args map[string]string) ([]PromptMessage, error)
+}
+
+// PromptArgument describes an argument accepted by a prompt.
+type PromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// PromptMessage is a templated prompt message. Role is "user" or "assistant"
+// and defaults to "user".
+type PromptMessage struct {
+	Role string
+	Text string
+}
+
+var promptPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// RegisterPrompt registers a prompt, replacing any prompt with the same name.
+func (mux *MCPServeMux) RegisterPrompt(prompt *Prompt) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	for i, existing := range mux.prompts {
+		if existing.Name == prompt.Name {
+			mux.prompts[i] = prompt
+			return
+		}
+	}
+	mux.prompts = append(mux.prompts, prompt)
+}
+
+func (mux *MCPServeMux) hasPrompts() bool {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	return len(mux.prompts) > 0
+}
+
+func (mux *MCPServeMux) findPrompt(name string) *Prompt {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	for _, prompt := range mux.prompts {
+		if prompt.Name == name {
+			return prompt
+		}
+	}
+	return nil
+}
+
+func (mux *MCPServeMux) handleListPrompts(ctx context.Context) (any, *MCPError) {
+	version := ProtocolVersionFromContext(ctx)
+
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+
+	prompts := make([]map[string]interface{}, 0, len(mux.prompts))
+	for _, prompt := range mux.prompts {
+		p := map[string]interface{}{
+			"name": prompt.Name,
+		}
+		if prompt.Title != "" && supportsTitles(version) {
+			p["title"] = prompt.Title
+		}
+		if prompt.Description != "" {
+			p["description"] = prompt.Description
+		}
+		if len(prompt.Arguments) > 0 {
+			args := make([]map[string]interface{}, 0, len(prompt.Arguments))
+			for _, arg := range prompt.Arguments {
+				a := map[string]interface{}{
+					"name": arg.Name,
+				}
+				if arg.Description != "" {
+					a["description"] = arg.Description
+				}
+				if arg.Required {
+					a["required"] = true
+				}
+				args = append(args, a)
+			}
+			p["arguments"] = args
+		}
+		prompts = append(prompts, p)
+	}
+
+	return map[string]interface{}{"prompts": prompts}, nil
+}
+
+func (mux *MCPServeMux) handleGetPrompt(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	name, ok := params["name"].(string)
+	if !ok {
+		return nil, &MCPError{Code: -32602, Message: "Missing prompt name"}
+	}
+	prompt := mux.findPrompt(name)
+	if prompt == nil {
+		return nil, &MCPError{Code: -32602, Message: fmt.Sprintf("Prompt not found: %s", name)}
+	}
+
+	args := map[string]string{}
+	if raw, ok := params["arguments"].(map[string]interface{}); ok {
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				args[k] = s
+			} else {
+				args[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	for _, arg := range prompt.Arguments {
+		if _, ok := args[arg.Name]; arg.Required && !ok {
+			return nil, &MCPError{Code: -32602, Message: fmt.Sprintf("Missing required argument: %s", arg.Name)}
+		}
+	}
+
+	messages := prompt.Messages
+	if prompt.Handler != nil {
+		var err error
+		if messages, err = prompt.Handler(ctx, args); err != nil {
+			return nil, &MCPError{Code: -32000, Message: err.Error()}
+		}
+	}
+
+	rendered := make([]map[string]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		role := msg.Role
+		if role == "" {
+			role = "user"
+		}
+		text := msg.Text
+		if prompt.Handler == nil {
+			text = renderPrompt(text, args)
+		}
+		rendered = append(rendered, map[string]interface{}{
+			"role": role,
+			"content": map[string]interface{}{
+				"type": "text",
+				"text": text,
+			},
+		})
+	}
+
+	result := map[string]interface{}{
+		"messages": rendered,
+	}
+	if prompt.Description != "" {
+		result["description"] = prompt.Description
+	}
+	return result, nil
+}
+
+// renderPrompt replaces {{name}} placeholders with argument values. Unknown
+// placeholders render as empty strings.
+func renderPrompt(text string, args map[string]string) string {
+	return promptPlaceholder.ReplaceAllStringFunc(text, func(m string) string {
+		return args[promptPlaceholder.FindStringSubmatch(m)[1]]
+	})
+}
@@ -0,0 +1,28 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrompts(t *testing.T) {
+	mux := newTestMux()
+	mux.RegisterPrompt(&Prompt{
+		Name:      "triage",
+		Arguments: []PromptArgument{{Name: "project", Required: true}, {Name: "owner"}},
+		Messages:  []PromptMessage{{Text: "Triage {{project}} for {{ owner }}."}},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "prompts/get", map[string]any{
+		"name":      "triage",
+		"arguments": map[string]any{"project": "apollo", "owner": "ada"},
+	}), nil)
+	if !strings.Contains(rec.Body.String(), `"text":"Triage apollo for ada."`) {
+		t.Fatalf("unexpected prompt: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, rpc(2, "prompts/get", map[string]any{"name": "triage"}), nil)
+	if !strings.Contains(rec.Body.String(), "Missing required argument: project") {
+		t.Fatalf("missing argument accepted: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,206 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ResourceHandler serves an MCP resource by invoking a gRPC method. A
+// URITemplate without variables is a concrete resource listed by
+// resources/list; one with {variables} is listed by resources/templates/list.
+// Template variables are passed to Handler as arguments keyed by name.
+type ResourceHandler struct {
+	URITemplate string
+	Name        string
+	Title       string
+	Description string
+	MIMEType    string
+	Handler     func(ctx context.Context, args map[string]any) (any, error)
+
+	pattern *regexp.Regexp
+	vars    []string
+}
+
+var uriTemplateExpr = regexp.MustCompile(`\{([+]?)([A-Za-z0-9_.]+)\}`)
+
+// compile prepares the URI template for matching. Simple {var} expressions
+// match a single path segment; reserved {+var} expressions match the rest.
+func (res *ResourceHandler) compile() {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, m := range uriTemplateExpr.FindAllStringSubmatchIndex(res.URITemplate, -1) {
+		pattern.WriteString(regexp.QuoteMeta(res.URITemplate[last:m[0]]))
+		if m[3] > m[2] {
+			pattern.WriteString("(.+)")
+		} else {
+			pattern.WriteString("([^/?#]+)")
+		}
+		res.vars = append(res.vars, res.URITemplate[m[4]:m[5]])
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(res.URITemplate[last:]))
+	pattern.WriteString("$")
+	res.pattern = regexp.MustCompile(pattern.String())
+}
+
+// isTemplate reports whether the resource URI contains variables.
+func (res *ResourceHandler) isTemplate() bool {
+	return len(res.vars) > 0
+}
+
+// match reports whether uri matches the template and returns the values of
+// its variables.
+func (res *ResourceHandler) match(uri string) (map[string]any, bool) {
+	m := res.pattern.FindStringSubmatch(uri)
+	if m == nil {
+		return nil, false
+	}
+	args := make(map[string]any, len(res.vars))
+	for i, name := range res.vars {
+		v, err := url.PathUnescape(m[i+1])
+		if err != nil {
+			return nil, false
+		}
+		args[name] = v
+	}
+	return args, true
+}
+
+func (res *ResourceHandler) mimeType() string {
+	if res.MIMEType != "" {
+		return res.MIMEType
+	}
+	return "application/json"
+}
+
+// RegisterResource registers a resource or resource template handler,
+// replacing any resource with the same URI template.
+func (mux *MCPServeMux) RegisterResource(res *ResourceHandler) {
+	res.compile()
+
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	for i, existing := range mux.resources {
+		if existing.URITemplate == res.URITemplate {
+			mux.resources[i] = res
+			return
+		}
+	}
+	mux.resources = append(mux.resources, res)
+}
+
+func (mux *MCPServeMux) hasResources() bool {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	return len(mux.resources) > 0
+}
+
+func (mux *MCPServeMux) handleListResources(ctx context.Context, templates bool) (any, *MCPError) {
+	version := ProtocolVersionFromContext(ctx)
+	uriKey, listKey := "uri", "resources"
+	if templates {
+		uriKey, listKey = "uriTemplate", "resourceTemplates"
+	}
+
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+
+	resources := make([]map[string]interface{}, 0, len(mux.resources))
+	for _, res := range mux.resources {
+		if res.isTemplate() != templates {
+			continue
+		}
+		r := map[string]interface{}{
+			uriKey:     res.URITemplate,
+			"name":     res.Name,
+			"mimeType": res.mimeType(),
+		}
+		if res.Title != "" && supportsTitles(version) {
+			r["title"] = res.Title
+		}
+		if res.Description != "" {
+			r["description"] = res.Description
+		}
+		resources = append(resources, r)
+	}
+
+	return map[string]interface{}{listKey: resources}, nil
+}
+
+func (mux *MCPServeMux) handleReadResource(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	uri, ok := params["uri"].(string)
+	if !ok || uri == "" {
+		return nil, &MCPError{Code: -32602, Message: "Missing resource uri"}
+	}
+
+	res, args := mux.findResource(uri)
+	if res == nil {
+		return nil, &MCPError{Code: -32002, Message: fmt.Sprintf("Resource not found: %s", uri)}
+	}
+
+	ctx = withJSONOptions(mux.outgoingMetadata(ctx), mux.jsonOptions)
+	args, err := mux.transformArgs(ctx, res.Name, args, nil)
+	if err != nil {
+		return nil, &MCPError{Code: -32602, Message: err.Error()}
+	}
+	output, err := res.Handler(ctx, args)
+	if err != nil {
+		var argErr *ArgumentError
+		if errors.As(err, &argErr) {
+			return nil, &MCPError{Code: -32602, Message: err.Error()}
+		}
+		return nil, &MCPError{Code: -32000, Message: err.Error()}
+	}
+
+	var text string
+	switch v := output.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, &MCPError{Code: -32603, Message: fmt.Sprintf("Failed to encode resource: %v", err)}
+		}
+		text = string(b)
+	}
+
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"uri":      uri,
+				"mimeType": res.mimeType(),
+				"text":     text,
+			},
+		},
+	}, nil
+}
+
+// findResource returns the resource serving uri. Concrete resources take
+// precedence over templates.
+func (mux *MCPServeMux) findResource(uri string) (*ResourceHandler, map[string]any) {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+
+	for _, res := range mux.resources {
+		if !res.isTemplate() && res.URITemplate == uri {
+			return res, map[string]any{}
+		}
+	}
+	for _, res := range mux.resources {
+		if !res.isTemplate() {
+			continue
+		}
+		if args, ok := res.match(uri); ok {
+			return res, args
+		}
+	}
+	return nil, nil
+}
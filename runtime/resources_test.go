@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResources(t *testing.T) {
+	mux := newTestMux()
+	mux.RegisterResource(&ResourceHandler{
+		URITemplate: "tasks://{task_id}",
+		Name:        "task",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return map[string]any{"id": args["task_id"]}, nil
+		},
+	})
+	mux.RegisterResource(&ResourceHandler{
+		URITemplate: "tasks://summary",
+		Name:        "summary",
+		MIMEType:    "text/plain",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return "3 open tasks", nil
+		},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "resources/templates/list", nil), nil)
+	if !strings.Contains(rec.Body.String(), `"uriTemplate":"tasks://{task_id}"`) || strings.Contains(rec.Body.String(), "summary") {
+		t.Fatalf("unexpected templates: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, rpc(2, "resources/read", map[string]any{"uri": "tasks://a%2Fb"}), nil)
+	var resp struct {
+		Result struct {
+			Contents []map[string]string `json:"contents"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Result.Contents) != 1 {
+		t.Fatalf("unexpected read response: %s", rec.Body.String())
+	}
+	if got := resp.Result.Contents[0]["text"]; got != `{"id":"a/b"}` {
+		t.Fatalf("got contents %s", got)
+	}
+
+	rec = postJSON(t, mux, rpc(3, "resources/read", map[string]any{"uri": "tasks://summary"}), nil)
+	if !strings.Contains(rec.Body.String(), `"text":"3 open tasks"`) {
+		t.Fatalf("static resource shadowed by template: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, rpc(4, "resources/read", map[string]any{"uri": "files://x"}), nil)
+	if !strings.Contains(rec.Body.String(), "-32002") {
+		t.Fatalf("expected resource not found: %s", rec.Body.String())
+	}
+}
@@ -1,20 +1,59 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-// MCPServeMux is a stateless request multiplexer for MCP JSON-RPC requests.
-// It routes MCP tool calls to registered gRPC handlers.
+// MCPServeMux is a request multiplexer for MCP JSON-RPC requests.
+// It routes MCP tool calls to registered gRPC handlers. The mux is stateless
+// unless WithSessions is used.
 type MCPServeMux struct {
 	mu            sync.RWMutex
 	tools         map[string]*ToolHandler
+	toolNames     []string
+	toolOrder     ToolOrder
+	toolsPageSize int
+	resources     []*ResourceHandler
+	prompts       []*Prompt
+	completions   map[completionKey]CompletionFunc
 	metadata      ServerMetadata
 	requestLogger RequestLogger
+
+	defaultLogLevel LogLevel
+	streamLimits    StreamLimits
+	argTransformers []ArgTransformer
+	jsonOptions     JSONOptions
+	coerceArgs      bool
+
+	defaultToolTimeout time.Duration
+	maxClientTimeout   time.Duration
+
+	toolErrorMappings map[codes.Code]ToolErrorMapping
+
+	incomingHeaderMatcher HeaderMatcherFunc
+	outgoingHeaderMatcher OutgoingHeaderMatcherFunc
+	defaultMetadata       bool
+
+	sessionsEnabled    bool
+	sessionIdleTimeout time.Duration
+	sessionsMu         sync.Mutex
+	sessions           map[string]*session
+	conns              map[*session]struct{}
+
+	inflightMu sync.Mutex
+	inflight   map[requestKey]*inflightCall
 }
 
 // ToolHandler handles an MCP tool call by invoking a gRPC method
@@ -23,9 +62,37 @@ type ToolHandler struct {
 	Title       string
 	Description string
 	InputSchema map[string]any
-	ReadOnly    bool
-	Idempotent  bool
-	Destructive bool
+	// InputDescriptor describes the request message the arguments decode
+	// into. When set, field paths in error details are reported with the
+	// JSON names clients use.
+	InputDescriptor protoreflect.MessageDescriptor
+	// ClientStreaming marks a tool for a client-streaming RPC, whose
+	// arguments carry one request message per element of "items".
+	// InputDescriptor then describes each item rather than the arguments.
+	ClientStreaming bool
+	// OutputSchema describes structuredContent. Results that are not JSON
+	// objects are wrapped as {"result": <value>}, so the schema must
+	// describe that wrapper for them.
+	OutputSchema map[string]any
+	ReadOnly     bool
+	Idempotent   bool
+	Destructive  bool
+	// Timeout bounds each call. Zero falls back to WithDefaultToolTimeout.
+	Timeout time.Duration
+	// SkipValidation turns off checking arguments against InputSchema
+	// before the handler runs.
+	SkipValidation bool
+	// ArgTransformers rewrite the arguments, in order, after the mux's
+	// WithArgTransformers chain and before validation.
+	ArgTransformers []ArgTransformer
+	// CoerceArgs rewrites arguments models commonly get wrong, such as "42"
+	// for an integer or "done" for an enum value, before validation. It needs
+	// InputDescriptor. WithArgCoercion turns it on for every tool.
+	CoerceArgs bool
+	// JSONOptions overrides the mux's WithJSONOptions for this tool. The
+	// OutputSchema is advertised only while the options in effect shape
+	// results as these do on top of the protojson defaults.
+	JSONOptions *ToolJSONOptions
 	Handler     func(ctx context.Context, args map[string]any) (any, error)
 }
 
@@ -50,12 +117,45 @@ func WithRequestLogger(logger RequestLogger) Option {
 	}
 }
 
-// NewMCPServeMux creates a new stateless MCP request multiplexer
+// WithSessions enables stateful Streamable HTTP sessions. The mux assigns an
+// Mcp-Session-Id at initialize, requires it on every later request, serves
+// server-initiated SSE streams on GET and terminates sessions on DELETE.
+func WithSessions() Option {
+	return func(mux *MCPServeMux) {
+		mux.sessionsEnabled = true
+	}
+}
+
+// WithSessionIdleTimeout evicts sessions that have seen no traffic for d.
+// Sessions with an open GET stream are never evicted. Zero disables eviction.
+func WithSessionIdleTimeout(d time.Duration) Option {
+	return func(mux *MCPServeMux) {
+		mux.sessionIdleTimeout = d
+	}
+}
+
+// NewMCPServeMux creates a new MCP request multiplexer
 func NewMCPServeMux(metadata ServerMetadata, opts ...Option) *MCPServeMux {
 	mux := &MCPServeMux{
-		tools:         make(map[string]*ToolHandler),
-		metadata:      metadata,
-		requestLogger: func(context.Context, *MCPRequest) {},
+		tools:                 make(map[string]*ToolHandler),
+		metadata:              metadata,
+		requestLogger:         func(context.Context, *MCPRequest) {},
+		sessions:              make(map[string]*session),
+		conns:                 make(map[*session]struct{}),
+		inflight:              make(map[requestKey]*inflightCall),
+		completions:           make(map[completionKey]CompletionFunc),
+		defaultLogLevel:       LogLevelInfo,
+		toolErrorMappings:     make(map[codes.Code]ToolErrorMapping),
+		incomingHeaderMatcher: DefaultHeaderMatcher,
+		outgoingHeaderMatcher: DefaultOutgoingHeaderMatcher,
+		defaultMetadata:       true,
+		streamLimits: StreamLimits{
+			MaxMessages: DefaultStreamMaxMessages,
+			MaxDuration: DefaultStreamMaxDuration,
+		},
+	}
+	for code, mapping := range DefaultToolErrorMappings {
+		mux.toolErrorMappings[code] = mapping
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -65,68 +165,305 @@ func NewMCPServeMux(metadata ServerMetadata, opts ...Option) *MCPServeMux {
 	return mux
 }
 
-// RegisterTool registers a new tool handler
+// RegisterTool registers a new tool handler, replacing any tool with the same
+// name. Connected sessions are sent notifications/tools/list_changed.
 func (mux *MCPServeMux) RegisterTool(tool *ToolHandler) {
 	mux.mu.Lock()
-	defer mux.mu.Unlock()
+	mux.addTool(tool)
+	mux.mu.Unlock()
+	mux.toolsChanged()
+}
+
+// UnregisterTool removes the named tool. It reports whether the tool was
+// registered; connected sessions are notified only if it was.
+func (mux *MCPServeMux) UnregisterTool(name string) bool {
+	mux.mu.Lock()
+	removed := mux.removeTool(name)
+	mux.mu.Unlock()
+	if removed {
+		mux.toolsChanged()
+	}
+	return removed
+}
+
+// ReplaceTools atomically replaces every registered tool with tools and sends
+// a single notifications/tools/list_changed to connected sessions.
+func (mux *MCPServeMux) ReplaceTools(tools ...*ToolHandler) {
+	mux.mu.Lock()
+	mux.tools = make(map[string]*ToolHandler, len(tools))
+	mux.toolNames = nil
+	for _, tool := range tools {
+		mux.addTool(tool)
+	}
+	mux.mu.Unlock()
+	mux.toolsChanged()
+}
+
+// addTool registers tool. The caller must hold mux.mu.
+func (mux *MCPServeMux) addTool(tool *ToolHandler) {
+	if _, exists := mux.tools[tool.Name]; !exists {
+		mux.toolNames = append(mux.toolNames, tool.Name)
+	}
 	mux.tools[tool.Name] = tool
 }
 
-// ServeHTTP implements http.Handler for stateless MCP JSON-RPC requests
+// removeTool unregisters the named tool. The caller must hold mux.mu.
+func (mux *MCPServeMux) removeTool(name string) bool {
+	if _, exists := mux.tools[name]; !exists {
+		return false
+	}
+	delete(mux.tools, name)
+	for i, n := range mux.toolNames {
+		if n == name {
+			mux.toolNames = append(mux.toolNames[:i:i], mux.toolNames[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// toolsChanged tells every connected session that the tool list changed.
+func (mux *MCPServeMux) toolsChanged() {
+	mux.broadcast(&MCPNotification{JSONRPC: "2.0", Method: "notifications/tools/list_changed"})
+}
+
+// orderedTools returns the registered tools in the configured order. The
+// caller must hold mux.mu.
+func (mux *MCPServeMux) orderedTools() []*ToolHandler {
+	names := mux.toolNames
+	if mux.toolOrder == ToolOrderName {
+		names = append([]string(nil), names...)
+		sort.Strings(names)
+	}
+	tools := make([]*ToolHandler, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, mux.tools[name])
+	}
+	return tools
+}
+
+// ServeHTTP implements http.Handler for the MCP Streamable HTTP transport.
+// POST carries client messages, GET opens a server-initiated SSE stream and
+// DELETE terminates a session. GET and DELETE require WithSessions.
 func (mux *MCPServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.Method == http.MethodOptions {
+	switch r.Method {
+	case http.MethodOptions:
 		w.WriteHeader(http.StatusNoContent)
-		return
+	case http.MethodPost:
+		mux.servePost(w, r)
+	case http.MethodGet:
+		mux.serveGet(w, r)
+	case http.MethodDelete:
+		mux.serveDelete(w, r)
+	default:
+		sendError(w, nil, -32600, "Invalid request method")
 	}
+}
 
-	if r.Method != http.MethodPost {
-		sendError(w, nil, -32600, "Invalid request method")
+func (mux *MCPServeMux) servePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		sendError(w, nil, -32700, fmt.Sprintf("Parse error: %v", err))
 		return
 	}
-
-	var req MCPRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	msgs, batch, err := decodeMessages(body)
+	if err != nil {
 		sendError(w, nil, -32700, fmt.Sprintf("Parse error: %v", err))
 		return
 	}
+	if len(msgs) == 0 {
+		sendError(w, nil, -32600, "Invalid Request: empty batch")
+		return
+	}
 
-	ctx := r.Context()
-	mux.requestLogger(ctx, &req)
+	var sess *session
+	if mux.sessionsEnabled {
+		if hasMethod(msgs, "initialize") {
+			sess = mux.newSession()
+			w.Header().Set(SessionIDHeader, sess.id)
+		} else {
+			var ok bool
+			if sess, ok = mux.sessionFromRequest(w, r); !ok {
+				return
+			}
+		}
+	}
+	ctx := withSession(r.Context(), sess)
+	ctx = mux.withIncomingHeaders(ctx, r.Header)
+	if !hasMethod(msgs, "initialize") {
+		var ok bool
+		if ctx, ok = protocolVersionFromRequest(ctx, w, r); !ok {
+			return
+		}
+	}
 
-	switch req.Method {
-	case "initialize":
-		if req.ID == nil {
-			sendError(w, nil, -32600, "Missing request id")
+	if !needsResponse(msgs) {
+		// Notifications and client responses are acknowledged without a body.
+		mux.dispatchAll(ctx, msgs)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if !acceptsEventStream(r) {
+		ctx, headers := withResponseHeaders(ctx)
+		responses := mux.dispatchAll(ctx, msgs)
+		headers.copyTo(w.Header())
+		if len(responses) == 0 {
+			// Every request was cancelled by the client, which expects no
+			// response to them. 202 is reserved for input without requests,
+			// so the call ends with an empty 200.
+			w.WriteHeader(http.StatusOK)
 			return
 		}
-		mux.handleInitialize(w, ctx, req.ID)
-	case "notifications/initialized":
-		// Client notification that initialization is complete.
-		// Per JSON-RPC 2.0 spec, notifications (ID == nil) don't expect a response.
-		// Just acknowledge it silently by sending empty 204 response.
-		w.WriteHeader(http.StatusNoContent)
-	case "tools/list":
-		if req.ID == nil {
-			sendError(w, nil, -32600, "Missing request id")
+		if batch {
+			sendJSON(w, responses)
 			return
 		}
-		mux.handleListTools(w, ctx, req.ID)
-	case "tools/call":
-		if req.ID == nil {
-			sendError(w, nil, -32600, "Missing request id")
+		resp := responses[0]
+		if resp.Error != nil {
+			sendError(w, resp.ID, resp.Error.Code, resp.Error.Message)
 			return
 		}
-		mux.handleCallTool(w, ctx, req.ID, req.Params)
+		sendSuccess(w, resp.ID, resp.Result)
+		return
+	}
+
+	stream := newSSEWriter(w)
+	// Handlers may still hold the stream after the response is written, e.g.
+	// in a goroutine that logs; their sends must not reach w once we return.
+	defer stream.close()
+	responses := mux.dispatchAll(withMessageSink(ctx, stream), msgs)
+	switch {
+	case len(responses) == 0:
+	case batch:
+		_ = stream.send(responses)
 	default:
-		// Per JSON-RPC 2.0 spec, notifications (requests with ID == nil) don't get error responses.
-		// Only respond with error if this was an actual request (has an ID).
-		if req.ID != nil {
-			sendError(w, req.ID, -32601, fmt.Sprintf("Method not found: %s", req.Method))
-		} else {
-			// Unknown notification - silently ignore
-			w.WriteHeader(http.StatusNoContent)
+		_ = stream.send(responses[0])
+	}
+}
+
+func (mux *MCPServeMux) serveGet(w http.ResponseWriter, r *http.Request) {
+	if !mux.sessionsEnabled {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !acceptsEventStream(r) {
+		w.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+	sess, ok := mux.sessionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := protocolVersionFromRequest(withSession(r.Context(), sess), w, r); !ok {
+		return
+	}
+
+	stream := newSSEWriter(w)
+	sess.attach(stream)
+	defer sess.detach(stream)
+
+	select {
+	case <-r.Context().Done():
+	case <-sess.done:
+	}
+}
+
+func (mux *MCPServeMux) serveDelete(w http.ResponseWriter, r *http.Request) {
+	if !mux.sessionsEnabled {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	sess, ok := mux.sessionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := protocolVersionFromRequest(withSession(r.Context(), sess), w, r); !ok {
+		return
+	}
+	mux.closeSession(sess)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dispatch routes a single JSON-RPC message to its method handler. It returns
+// nil when the message is a notification and no response must be sent.
+func (mux *MCPServeMux) dispatch(ctx context.Context, req *MCPRequest) *MCPResponse {
+	if req.ID == nil {
+		if isRequestMethod(req.Method) {
+			return errorResponse(nil, -32600, "Missing request id")
+		}
+		// Per JSON-RPC 2.0 spec, notifications (ID == nil) don't expect a response.
+		// Unknown notifications are silently ignored.
+		if req.Method == "notifications/cancelled" {
+			mux.handleCancelled(ctx, req.Params)
 		}
+		return nil
 	}
+
+	ctx = context.WithValue(ctx, defaultLogLevelKey{}, mux.defaultLogLevel)
+	ctx = withRequestID(ctx, req.ID)
+
+	var result any
+	var rpcErr *MCPError
+	switch req.Method {
+	case "initialize":
+		result, rpcErr = mux.handleInitialize(ctx, req.Params)
+	case "tools/list":
+		result, rpcErr = mux.handleListTools(ctx, req.Params)
+	case "ping":
+		result = map[string]interface{}{}
+	case "resources/list":
+		result, rpcErr = mux.handleListResources(ctx, false)
+	case "resources/templates/list":
+		result, rpcErr = mux.handleListResources(ctx, true)
+	case "resources/read":
+		result, rpcErr = mux.handleReadResource(ctx, req.Params)
+	case "prompts/list":
+		result, rpcErr = mux.handleListPrompts(ctx)
+	case "prompts/get":
+		result, rpcErr = mux.handleGetPrompt(ctx, req.Params)
+	case "completion/complete":
+		result, rpcErr = mux.handleComplete(ctx, req.Params)
+	case "logging/setLevel":
+		result, rpcErr = mux.handleSetLogLevel(ctx, req.Params)
+	case "tools/call":
+		callCtx, call, done := mux.trackRequest(ctx, req.ID)
+		result, rpcErr = mux.handleCallTool(callCtx, req.Params)
+		done()
+		if call.cancelled.Load() {
+			// The client cancelled the call and expects no response.
+			return nil
+		}
+	default:
+		rpcErr = &MCPError{Code: -32601, Message: fmt.Sprintf("Method not found: %s", req.Method)}
+	}
+
+	if rpcErr != nil {
+		return &MCPResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &MCPResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// expectsResponse reports whether req is a request rather than a notification
+// or a client response to a server-initiated request.
+func expectsResponse(req *MCPRequest) bool {
+	if req.Method == "" {
+		return false
+	}
+	return req.ID != nil || isRequestMethod(req.Method)
+}
+
+// isRequestMethod reports whether method is a request that must carry an ID.
+func isRequestMethod(method string) bool {
+	switch method {
+	case "initialize", "ping", "tools/list", "tools/call",
+		"resources/list", "resources/templates/list", "resources/read",
+		"prompts/list", "prompts/get", "completion/complete", "logging/setLevel":
+		return true
+	}
+	return false
 }
 
 // MCPRequest represents an MCP JSON-RPC request
@@ -145,40 +482,79 @@ type MCPResponse struct {
 	Error   *MCPError   `json:"error,omitempty"`
 }
 
+// MCPNotification represents a server-to-client JSON-RPC notification
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 // MCPError represents an MCP JSON-RPC error
 type MCPError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
-func (mux *MCPServeMux) handleInitialize(w http.ResponseWriter, ctx context.Context, id interface{}) {
+func (mux *MCPServeMux) handleInitialize(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	requested, _ := params["protocolVersion"].(string)
+	version := negotiateProtocolVersion(requested)
+	if sess := sessionFromContext(ctx); sess != nil {
+		sess.setProtocolVersion(version)
+	}
+
+	tools := map[string]interface{}{}
+	capabilities := map[string]interface{}{
+		"tools": tools,
+	}
+	if sessionFromContext(ctx) != nil {
+		// Only sessions and stdio connections have a channel for
+		// notifications/tools/list_changed and notifications/message.
+		tools["listChanged"] = true
+		capabilities["logging"] = map[string]interface{}{}
+	}
+	if mux.hasResources() {
+		capabilities["resources"] = map[string]interface{}{}
+	}
+	if mux.hasPrompts() {
+		capabilities["prompts"] = map[string]interface{}{}
+	}
+	if mux.hasCompletions() {
+		capabilities["completions"] = map[string]interface{}{}
+	}
+
 	result := map[string]interface{}{
-		"protocolVersion": "2025-11-25",
+		"protocolVersion": version,
 		"serverInfo": map[string]interface{}{
 			"name":    mux.metadata.Name,
 			"version": mux.metadata.Version,
 		},
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{
-				"listChanged": false,
-			},
-		},
+		"capabilities": capabilities,
 	}
 
-	sendSuccess(w, id, result)
+	return result, nil
 }
 
-func (mux *MCPServeMux) handleListTools(w http.ResponseWriter, ctx context.Context, id interface{}) {
+func (mux *MCPServeMux) handleListTools(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
+	version := ProtocolVersionFromContext(ctx)
+	cursor, _ := params["cursor"].(string)
+	offset, ok := decodeCursor(cursor)
+	if !ok {
+		return nil, &MCPError{Code: -32602, Message: "Invalid cursor"}
+	}
+
 	mux.mu.RLock()
 	defer mux.mu.RUnlock()
 
-	tools := make([]map[string]interface{}, 0, len(mux.tools))
-	for _, tool := range mux.tools {
+	ordered := mux.orderedTools()
+	start, end, nextCursor := paginate(len(ordered), offset, mux.toolsPageSize)
+
+	tools := make([]map[string]interface{}, 0, end-start)
+	for _, tool := range ordered[start:end] {
 		t := map[string]interface{}{
 			"name":        tool.Name,
 			"description": tool.Description,
 		}
-		if tool.Title != "" {
+		if tool.Title != "" && supportsTitles(version) {
 			t["title"] = tool.Title
 		}
 		if tool.InputSchema != nil {
@@ -186,6 +562,9 @@ func (mux *MCPServeMux) handleListTools(w http.ResponseWriter, ctx context.Conte
 		} else {
 			t["inputSchema"] = DefaultInputSchema()
 		}
+		if tool.OutputSchema != nil && supportsStructuredContent(version) && mux.jsonOptions.merge(tool.JSONOptions).sameOutputShape(JSONOptions{}.merge(tool.JSONOptions)) {
+			t["outputSchema"] = tool.OutputSchema
+		}
 
 		annotations := make(map[string]interface{})
 		if tool.ReadOnly {
@@ -197,7 +576,7 @@ func (mux *MCPServeMux) handleListTools(w http.ResponseWriter, ctx context.Conte
 		if tool.Destructive {
 			annotations["destructiveHint"] = true
 		}
-		if len(annotations) > 0 {
+		if len(annotations) > 0 && supportsToolAnnotations(version) {
 			t["annotations"] = annotations
 		}
 
@@ -207,8 +586,11 @@ func (mux *MCPServeMux) handleListTools(w http.ResponseWriter, ctx context.Conte
 	result := map[string]interface{}{
 		"tools": tools,
 	}
+	if nextCursor != "" {
+		result["nextCursor"] = nextCursor
+	}
 
-	sendSuccess(w, id, result)
+	return result, nil
 }
 
 // DefaultInputSchema provides a permissive object schema for tool inputs.
@@ -220,11 +602,10 @@ func DefaultInputSchema() map[string]any {
 	}
 }
 
-func (mux *MCPServeMux) handleCallTool(w http.ResponseWriter, ctx context.Context, id interface{}, params map[string]interface{}) {
+func (mux *MCPServeMux) handleCallTool(ctx context.Context, params map[string]interface{}) (any, *MCPError) {
 	toolName, ok := params["name"].(string)
 	if !ok {
-		sendError(w, id, -32602, "Missing tool name")
-		return
+		return nil, &MCPError{Code: -32602, Message: "Missing tool name"}
 	}
 
 	arguments, _ := params["arguments"].(map[string]interface{})
@@ -234,15 +615,59 @@ func (mux *MCPServeMux) handleCallTool(w http.ResponseWriter, ctx context.Contex
 	mux.mu.RUnlock()
 
 	if !exists {
-		sendError(w, id, -32601, fmt.Sprintf("Tool not found: %s", toolName))
-		return
+		return nil, &MCPError{Code: -32601, Message: fmt.Sprintf("Tool not found: %s", toolName)}
 	}
 
 	// Call the tool handler
+	ctx = withToolName(ctx, toolName)
+	ctx = mux.outgoingMetadata(ctx)
+	ctx = context.WithValue(ctx, streamLimitsKey{}, mux.streamLimits)
+	jsonOptions := mux.jsonOptions.merge(tool.JSONOptions)
+	ctx = withJSONOptions(ctx, jsonOptions)
+	if token, ok := progressToken(params); ok {
+		ctx = withProgressToken(ctx, token)
+	}
+	timeout, rpcErr := mux.toolTimeout(tool, params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	arguments, err := mux.transformArgs(ctx, toolName, arguments, tool.ArgTransformers)
+	if err != nil {
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return mux.toolErrorResult(ctx, err, tool.InputDescriptor), nil
+	}
+	var coercions []ArgCoercion
+	if mux.coerceArgs || tool.CoerceArgs {
+		coercions = coerceToolArgs(tool, arguments)
+	}
+	if !tool.SkipValidation {
+		if violations := validateToolArgs(tool, arguments, jsonOptions.DiscardUnknown); len(violations) > 0 {
+			result := mux.toolErrorResult(ctx, &ArgumentError{Violations: violations}, tool.InputDescriptor)
+			addCoercions(result, coercions)
+			return result, nil
+		}
+	}
+
+	ctx, md := withResponseMetadata(ctx)
 	output, err := tool.Handler(ctx, arguments)
 	if err != nil {
-		sendError(w, id, -32000, err.Error())
-		return
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = timeoutError(toolName, timeout)
+		}
+		result := mux.toolErrorResult(ctx, err, tool.InputDescriptor)
+		mux.applyResponseMetadata(ctx, md, result)
+		addCoercions(result, coercions)
+		return result, nil
 	}
 
 	var text string
@@ -258,6 +683,9 @@ func (mux *MCPServeMux) handleCallTool(w http.ResponseWriter, ctx context.Contex
 		if b, marshalErr := json.Marshal(v); marshalErr == nil {
 			text = string(b)
 			structuredContent = v
+			if !isJSONObject(b) {
+				structuredContent = map[string]interface{}{"result": v}
+			}
 		} else {
 			text = fmt.Sprintf("%v", v)
 		}
@@ -273,11 +701,20 @@ func (mux *MCPServeMux) handleCallTool(w http.ResponseWriter, ctx context.Contex
 		},
 		"isError": false,
 	}
-	if structuredContent != nil {
+	if structuredContent != nil && supportsStructuredContent(ProtocolVersionFromContext(ctx)) {
 		response["structuredContent"] = structuredContent
 	}
+	mux.applyResponseMetadata(ctx, md, response)
+	addCoercions(response, coercions)
 
-	sendSuccess(w, id, response)
+	return response, nil
+}
+
+// isJSONObject reports whether b encodes a JSON object. structuredContent
+// must be one.
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{'
 }
 
 func sendSuccess(w http.ResponseWriter, id interface{}, result interface{}) {
@@ -286,15 +723,18 @@ func sendSuccess(w http.ResponseWriter, id interface{}, result interface{}) {
 		ID:      id,
 		Result:  result,
 	}
+	sendJSON(w, response)
+}
 
+func sendJSON(w http.ResponseWriter, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 
-func sendError(w http.ResponseWriter, id interface{}, code int, message string) {
-	response := MCPResponse{
+func errorResponse(id interface{}, code int, message string) *MCPResponse {
+	return &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 		Error: &MCPError{
@@ -302,14 +742,29 @@ func sendError(w http.ResponseWriter, id interface{}, code int, message string)
 			Message: message,
 		},
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
+func sendError(w http.ResponseWriter, id interface{}, code int, message string) {
 	status := http.StatusOK
 	if code == -32600 || code == -32601 {
 		status = http.StatusBadRequest
 	} else if code == -32700 {
 		status = http.StatusBadRequest
 	}
+	sendErrorStatus(w, id, code, message, status)
+}
+
+func sendErrorStatus(w http.ResponseWriter, id interface{}, code int, message string, status int) {
+	response := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &MCPError{
+			Code:    code,
+			Message: message,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestToolListChangedNotification(t *testing.T) {
+	mux := newTestMux()
+	rec := postJSON(t, mux, rpc(1, "initialize", nil), nil)
+	if strings.Contains(rec.Body.String(), "listChanged") {
+		t.Fatalf("stateless mux advertised listChanged: %s", rec.Body.String())
+	}
+
+	pr, pw := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- mux.ServeConn(context.Background(), pr, outW) }()
+	lines := bufio.NewScanner(outR)
+
+	fmt.Fprintln(pw, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if !lines.Scan() {
+		t.Fatal("no ping response")
+	}
+
+	// Nobody reads the connection yet: registry changes must not wait for it.
+	if !mux.UnregisterTool("echo") {
+		t.Fatal("echo was not registered")
+	}
+	if !lines.Scan() || lines.Text() != `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}` {
+		t.Fatalf("unexpected message: %s", lines.Text())
+	}
+	if mux.UnregisterTool("echo") {
+		t.Fatal("echo unregistered twice")
+	}
+
+	pw.Close()
+	go io.Copy(io.Discard, outR)
+	if err := <-done; err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+}
+
+func TestOutputSchema(t *testing.T) {
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "list",
+		OutputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"result": map[string]any{"type": "array"}},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return []any{"a", "b"}, nil
+		},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "tools/list", nil), latestVersion)
+	if !strings.Contains(rec.Body.String(), `"outputSchema"`) {
+		t.Fatalf("outputSchema not advertised: %s", rec.Body.String())
+	}
+	rec = postJSON(t, mux, rpc(2, "tools/list", nil), http.Header{ProtocolVersionHeader: {"2025-03-26"}})
+	if strings.Contains(rec.Body.String(), `"outputSchema"`) {
+		t.Fatalf("outputSchema sent to 2025-03-26 client: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, rpc(3, "tools/call", map[string]any{"name": "list"}), latestVersion)
+	var resp struct {
+		Result struct {
+			StructuredContent map[string]any `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got := fmt.Sprint(resp.Result.StructuredContent); got != "map[result:[a b]]" {
+		t.Fatalf("structuredContent: got %s", got)
+	}
+}
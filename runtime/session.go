@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SessionIDHeader is the HTTP header carrying the MCP session ID.
+const SessionIDHeader = "Mcp-Session-Id"
+
+// session holds the state of one stateful MCP client connection.
+type session struct {
+	id   string
+	done chan struct{}
+
+	mu              sync.Mutex
+	streams         []*sessionStream
+	lastSeen        time.Time
+	closed          bool
+	protocolVersion string
+	logLevel        LogLevel
+}
+
+// messageSink delivers server-to-client JSON-RPC messages.
+type messageSink interface {
+	send(msg any) error
+}
+
+// streamQueueSize is how many broadcasts may wait for a slow stream before
+// further ones are dropped.
+const streamQueueSize = 32
+
+// sessionStream is a stream attached to a session. Broadcasts are queued and
+// written by the stream's own goroutine, so a slow reader delays only itself.
+type sessionStream struct {
+	sink  messageSink
+	queue chan any
+	stop  chan struct{}
+}
+
+func (st *sessionStream) run() {
+	for {
+		select {
+		case msg := <-st.queue:
+			_ = st.sink.send(msg)
+		case <-st.stop:
+			return
+		}
+	}
+}
+
+type sessionKey struct{}
+
+type messageSinkKey struct{}
+
+func withSession(ctx context.Context, sess *session) context.Context {
+	if sess == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// SessionIDFromContext returns the MCP session ID of the request, if any.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sess := sessionFromContext(ctx)
+	if sess == nil {
+		return "", false
+	}
+	return sess.id, true
+}
+
+func withMessageSink(ctx context.Context, sink messageSink) context.Context {
+	return context.WithValue(ctx, messageSinkKey{}, sink)
+}
+
+// notify sends a notification related to the request in ctx. It prefers the
+// request's own response stream and falls back to the session's GET stream.
+func notify(ctx context.Context, method string, params any) error {
+	msg := &MCPNotification{JSONRPC: "2.0", Method: method, Params: params}
+	if sink, ok := ctx.Value(messageSinkKey{}).(messageSink); ok {
+		return sink.send(msg)
+	}
+	if sess := sessionFromContext(ctx); sess != nil {
+		return sess.send(msg)
+	}
+	return nil
+}
+
+func (mux *MCPServeMux) newSession() *session {
+	now := time.Now()
+	sess := &session{
+		id:       newSessionID(),
+		done:     make(chan struct{}),
+		lastSeen: now,
+	}
+
+	mux.sessionsMu.Lock()
+	defer mux.sessionsMu.Unlock()
+	if mux.sessionIdleTimeout > 0 {
+		for id, s := range mux.sessions {
+			if s.idleSince(now) > mux.sessionIdleTimeout {
+				s.close()
+				delete(mux.sessions, id)
+			}
+		}
+	}
+	mux.sessions[sess.id] = sess
+	return sess
+}
+
+// sessionFromRequest resolves the session named by the request header. It
+// writes an error response and returns false when the session is missing.
+func (mux *MCPServeMux) sessionFromRequest(w http.ResponseWriter, r *http.Request) (*session, bool) {
+	id := r.Header.Get(SessionIDHeader)
+	if id == "" {
+		sendErrorStatus(w, nil, -32600, "Missing session id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	mux.sessionsMu.Lock()
+	sess, ok := mux.sessions[id]
+	mux.sessionsMu.Unlock()
+	if !ok {
+		sendErrorStatus(w, nil, -32001, "Session not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	sess.touch()
+	return sess, true
+}
+
+// addConn registers a session that is not addressable over HTTP, such as a
+// stdio connection, so that it receives broadcasts.
+func (mux *MCPServeMux) addConn(sess *session) {
+	mux.sessionsMu.Lock()
+	mux.conns[sess] = struct{}{}
+	mux.sessionsMu.Unlock()
+}
+
+func (mux *MCPServeMux) removeConn(sess *session) {
+	mux.sessionsMu.Lock()
+	delete(mux.conns, sess)
+	mux.sessionsMu.Unlock()
+	sess.close()
+}
+
+// broadcast queues msg for every connected session. It never waits for a
+// client to read.
+func (mux *MCPServeMux) broadcast(msg any) {
+	mux.sessionsMu.Lock()
+	targets := make([]*session, 0, len(mux.sessions)+len(mux.conns))
+	for _, sess := range mux.sessions {
+		targets = append(targets, sess)
+	}
+	for sess := range mux.conns {
+		targets = append(targets, sess)
+	}
+	mux.sessionsMu.Unlock()
+
+	for _, sess := range targets {
+		sess.post(msg)
+	}
+}
+
+func (mux *MCPServeMux) closeSession(sess *session) {
+	mux.sessionsMu.Lock()
+	delete(mux.sessions, sess.id)
+	mux.sessionsMu.Unlock()
+	sess.close()
+}
+
+func (s *session) touch() {
+	s.mu.Lock()
+	s.lastSeen = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *session) idleSince(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.streams) > 0 {
+		return 0
+	}
+	return now.Sub(s.lastSeen)
+}
+
+func (s *session) setProtocolVersion(version string) {
+	s.mu.Lock()
+	s.protocolVersion = version
+	s.mu.Unlock()
+}
+
+func (s *session) negotiatedVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocolVersion
+}
+
+func (s *session) setLogLevel(level LogLevel) {
+	s.mu.Lock()
+	s.logLevel = level
+	s.mu.Unlock()
+}
+
+func (s *session) getLogLevel() LogLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logLevel
+}
+
+func (s *session) attach(sink messageSink) {
+	st := &sessionStream{sink: sink, queue: make(chan any, streamQueueSize), stop: make(chan struct{})}
+	go st.run()
+	s.mu.Lock()
+	s.streams = append(s.streams, st)
+	s.mu.Unlock()
+}
+
+// detach removes sink from the session and closes it, if it can be closed.
+// send copies the stream list before writing, so a send racing with detach
+// may still reach sink; closing it turns that write into errStreamClosed.
+func (s *session) detach(sink messageSink) {
+	s.mu.Lock()
+	for i, st := range s.streams {
+		if st.sink == sink {
+			s.streams = append(s.streams[:i], s.streams[i+1:]...)
+			close(st.stop)
+			break
+		}
+	}
+	s.lastSeen = time.Now()
+	s.mu.Unlock()
+	if c, ok := sink.(interface{ close() }); ok {
+		c.close()
+	}
+}
+
+// send delivers msg on the first open server-initiated stream. Messages are
+// dropped when the client has no stream open.
+func (s *session) send(msg any) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errStreamClosed
+	}
+	streams := append([]*sessionStream(nil), s.streams...)
+	s.mu.Unlock()
+
+	var err error
+	for _, st := range streams {
+		if err = st.sink.send(msg); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// post queues msg on the first open server-initiated stream with room for
+// it. Messages are dropped when the client has no stream open or is not
+// keeping up.
+func (s *session) post(msg any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for _, st := range s.streams {
+		select {
+		case st.queue <- msg:
+			return
+		default:
+		}
+	}
+}
+
+func (s *session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	mux := newTestMux(WithSessions())
+
+	rec := postJSON(t, mux, rpc(1, "initialize", nil), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("initialize status: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	id := rec.Header().Get(SessionIDHeader)
+	if id == "" {
+		t.Fatal("initialize did not assign a session id")
+	}
+
+	if rec := postJSON(t, mux, rpc(2, "tools/list", nil), nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing session id: got status %d, want 400", rec.Code)
+	}
+
+	withID := http.Header{SessionIDHeader: {id}}
+	if rec := postJSON(t, mux, rpc(nil, "notifications/initialized", nil), withID); rec.Code != http.StatusAccepted {
+		t.Fatalf("notification: got status %d, want 202", rec.Code)
+	}
+	if rec := postJSON(t, mux, rpc(3, "tools/list", nil), withID); rec.Code != http.StatusOK {
+		t.Fatalf("tools/list: got status %d, want 200", rec.Code)
+	}
+
+	del := httptest.NewRequest(http.MethodDelete, "/", nil)
+	del.Header.Set(SessionIDHeader, id)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, del)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: got status %d, want 204", rec.Code)
+	}
+
+	if rec := postJSON(t, mux, rpc(4, "tools/list", nil), withID); rec.Code != http.StatusNotFound {
+		t.Fatalf("terminated session: got status %d, want 404", rec.Code)
+	}
+}
+
+func TestStatelessRejectsGet(t *testing.T) {
+	mux := newTestMux()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetachedStreamIsClosed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := newSSEWriter(rec)
+	sess := &session{id: "s", done: make(chan struct{})}
+	sess.attach(stream)
+	sess.detach(stream)
+
+	before := rec.Body.Len()
+	if err := stream.send(&MCPNotification{JSONRPC: "2.0", Method: "notifications/tools/list_changed"}); !errors.Is(err, errStreamClosed) {
+		t.Fatalf("send after detach: got %v, want errStreamClosed", err)
+	}
+	if rec.Body.Len() != before {
+		t.Fatalf("detached stream was written to: %q", rec.Body.String())
+	}
+}
+
+func TestSSEResponse(t *testing.T) {
+	mux := newTestMux()
+	header := http.Header{"Accept": {"application/json, text/event-stream"}}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{
+		"name":      "echo",
+		"arguments": map[string]any{"a": "b"},
+	}), header)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type: got %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "event: message\ndata: ") {
+		t.Fatalf("unexpected SSE framing: %q", body)
+	}
+	var resp MCPResponse
+	data := strings.TrimSpace(strings.TrimPrefix(body, "event: message\ndata: "))
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if resp.Error != nil || resp.ID != float64(1) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestSSEResponseClosedAfterReturn(t *testing.T) {
+	release := make(chan struct{})
+	logged := make(chan struct{})
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name: "detach",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			go func() {
+				<-release
+				LoggerFromContext(ctx).Warningf("too late")
+				close(logged)
+			}()
+			return "ok", nil
+		},
+	})
+
+	header := http.Header{"Accept": {"application/json, text/event-stream"}}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "detach"}), header)
+	body := rec.Body.String()
+	close(release)
+	<-logged
+	if rec.Body.String() != body {
+		t.Fatalf("late message written after the response: %q", strings.TrimPrefix(rec.Body.String(), body))
+	}
+}
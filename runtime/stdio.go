@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ServeStdio serves MCP over newline-delimited JSON-RPC on os.Stdin and
+// os.Stdout, as used by clients that launch servers as subprocesses.
+// Nothing else may write to stdout while it runs; send logs to stderr.
+func (mux *MCPServeMux) ServeStdio(ctx context.Context) error {
+	return mux.ServeConn(ctx, os.Stdin, os.Stdout)
+}
+
+// ServeConn serves newline-delimited JSON-RPC messages read from r and writes
+// responses and notifications to w. The connection is a single MCP session.
+// Tool calls run concurrently. ServeConn returns nil when r reaches EOF and
+// all in-flight calls have completed, or ctx.Err() when ctx is cancelled.
+func (mux *MCPServeMux) ServeConn(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := &lineWriter{w: w}
+	sess := &session{
+		id:   newSessionID(),
+		done: make(chan struct{}),
+	}
+	sess.attach(out)
+	defer sess.detach(out)
+	mux.addConn(sess)
+	defer mux.removeConn(sess)
+	ctx = withSession(ctx, sess)
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr <- err
+				}
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				wg.Wait()
+				select {
+				case err := <-readErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			mux.serveLine(ctx, &wg, out, line)
+		}
+	}
+}
+
+func (mux *MCPServeMux) serveLine(ctx context.Context, wg *sync.WaitGroup, out *lineWriter, line []byte) {
+	msgs, batch, err := decodeMessages(line)
+	if err != nil {
+		_ = out.send(errorResponse(nil, -32700, fmt.Sprintf("Parse error: %v", err)))
+		return
+	}
+	if len(msgs) == 0 {
+		_ = out.send(errorResponse(nil, -32600, "Invalid Request: empty batch"))
+		return
+	}
+
+	serve := func() {
+		responses := mux.dispatchAll(ctx, msgs)
+		switch {
+		case len(responses) == 0:
+		case batch:
+			_ = out.send(responses)
+		default:
+			_ = out.send(responses[0])
+		}
+	}
+	if !hasMethod(msgs, "tools/call") {
+		serve()
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve()
+	}()
+}
+
+// lineWriter writes newline-delimited JSON-RPC messages. Once closed it
+// drops everything, so queued broadcasts cannot write after ServeConn returns.
+type lineWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+func (lw *lineWriter) close() {
+	lw.mu.Lock()
+	lw.closed = true
+	lw.mu.Unlock()
+}
+
+func (lw *lineWriter) send(msg any) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if lw.closed {
+		return errStreamClosed
+	}
+	_, err = lw.w.Write(b)
+	return err
+}
@@ -0,0 +1,32 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServeConn(t *testing.T) {
+	mux := newTestMux()
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"x":1}}}`,
+	}, "\n"))
+	var out bytes.Buffer
+	if err := mux.ServeConn(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeConn: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d responses, want 3:\n%s", len(lines), out.String())
+	}
+	var parseErr MCPResponse
+	if err := json.Unmarshal([]byte(lines[1]), &parseErr); err != nil || parseErr.Error == nil || parseErr.Error.Code != -32700 {
+		t.Fatalf("expected parse error, got %s", lines[1])
+	}
+}
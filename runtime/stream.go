@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Default bounds applied to streams exposed as tools.
+const (
+	DefaultStreamMaxMessages = 100
+	DefaultStreamMaxDuration = 30 * time.Second
+	DefaultStreamMaxItems    = 1000
+)
+
+// StreamLimits bounds how much of a server stream a tool call collects. Zero
+// fields fall back to the mux defaults.
+type StreamLimits struct {
+	MaxMessages int
+	MaxDuration time.Duration
+}
+
+// StreamReceiver is the receiving side of a gRPC server stream.
+type StreamReceiver[T proto.Message] interface {
+	Recv() (T, error)
+}
+
+type streamLimitsKey struct{}
+
+// WithStreamLimits sets the default limits for server streams exposed as
+// tools. Tools may override them individually.
+func WithStreamLimits(limits StreamLimits) Option {
+	return func(mux *MCPServeMux) {
+		if limits.MaxMessages > 0 {
+			mux.streamLimits.MaxMessages = limits.MaxMessages
+		}
+		if limits.MaxDuration > 0 {
+			mux.streamLimits.MaxDuration = limits.MaxDuration
+		}
+	}
+}
+
+// streamLimitsFromContext merges limits with the mux defaults in ctx.
+func streamLimitsFromContext(ctx context.Context, limits StreamLimits) StreamLimits {
+	defaults, ok := ctx.Value(streamLimitsKey{}).(StreamLimits)
+	if !ok {
+		defaults = StreamLimits{MaxMessages: DefaultStreamMaxMessages, MaxDuration: DefaultStreamMaxDuration}
+	}
+	if limits.MaxMessages <= 0 {
+		limits.MaxMessages = defaults.MaxMessages
+	}
+	if limits.MaxDuration <= 0 {
+		limits.MaxDuration = defaults.MaxDuration
+	}
+	return limits
+}
+
+// CollectStream opens a server stream and drains it until it ends, limits
+// are reached or ctx is done. It returns the encoded messages as an array.
+// Reaching a limit is not an error; the stream is cancelled and the messages
+// received so far are returned.
+//
+// Every message is also forwarded to the client as it arrives: as a progress
+// notification when the call carries a progress token, as a debug log message
+// otherwise, which clients only see after lowering their log level.
+func CollectStream[T proto.Message](ctx context.Context, limits StreamLimits, open func(ctx context.Context) (StreamReceiver[T], error)) (any, error) {
+	limits = streamLimitsFromContext(ctx, limits)
+	streamCtx, cancel := context.WithTimeout(ctx, limits.MaxDuration)
+	defer cancel()
+
+	stream, err := open(streamCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := ProgressFromContext(ctx)
+	logger := LoggerFromContext(ctx)
+	messages := []any{}
+	for len(messages) < limits.MaxMessages {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			recordStreamMetadata(ctx, stream, true)
+			return messages, nil
+		}
+		if err != nil {
+			recordStreamMetadata(ctx, stream, true)
+			// Running out of time is a limit, unless the caller's own
+			// context expired.
+			if ctx.Err() == nil && errors.Is(streamCtx.Err(), context.DeadlineExceeded) {
+				return messages, nil
+			}
+			return nil, err
+		}
+		out, err := EncodeProtoContext(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, out)
+
+		if progress.Enabled() {
+			text, _ := json.Marshal(out)
+			progress.Report(float64(len(messages)), 0, string(text))
+		} else {
+			logger.Log(LogLevelDebug, out)
+		}
+	}
+	recordStreamMetadata(ctx, stream, false)
+	return messages, nil
+}
+
+// StreamItems returns the "items" argument of a client-streaming tool call,
+// one argument object per message to send. It fails when items is not an
+// array of objects or holds more than maxItems entries, with an
+// *ArgumentError; maxItems <= 0 means DefaultStreamMaxItems.
+func StreamItems(args map[string]any, maxItems int) ([]map[string]any, error) {
+	if maxItems <= 0 {
+		maxItems = DefaultStreamMaxItems
+	}
+	raw, ok := args["items"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, &ArgumentError{Violations: []ArgumentViolation{{
+			Pointer: "/items", Path: "items", Message: "must be an array", Expected: "array", Got: describeJSON(raw),
+		}}}
+	}
+	if len(list) > maxItems {
+		return nil, &ArgumentError{Violations: []ArgumentViolation{{
+			Pointer: "/items", Path: "items", Message: fmt.Sprintf("must have at most %d items", maxItems),
+		}}}
+	}
+	items := make([]map[string]any, len(list))
+	for i, v := range list {
+		item, ok := v.(map[string]any)
+		if !ok {
+			pointer := "/items/" + strconv.Itoa(i)
+			return nil, &ArgumentError{Violations: []ArgumentViolation{{
+				Pointer: pointer, Path: fieldPath(pointer), Message: "must be an object", Expected: "object", Got: describeJSON(v),
+			}}}
+		}
+		items[i] = item
+	}
+	return items, nil
+}
@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type fakeStream struct {
+	ctx    context.Context
+	values []string
+}
+
+func (s *fakeStream) Recv() (*structpb.Struct, error) {
+	if len(s.values) == 0 {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	v := s.values[0]
+	s.values = s.values[1:]
+	if v == "EOF" {
+		return nil, io.EOF
+	}
+	return structpb.NewStruct(map[string]any{"v": v})
+}
+
+func TestCollectStream(t *testing.T) {
+	collect := func(limits StreamLimits, values ...string) (any, error) {
+		return CollectStream(context.Background(), limits, func(ctx context.Context) (StreamReceiver[*structpb.Struct], error) {
+			return &fakeStream{ctx: ctx, values: values}, nil
+		})
+	}
+
+	got, err := collect(StreamLimits{}, "a", "b", "EOF")
+	if err != nil || fmt.Sprint(got) != "[map[v:a] map[v:b]]" {
+		t.Fatalf("drained stream: got %v, %v", got, err)
+	}
+	got, err = collect(StreamLimits{MaxMessages: 2}, "a", "b", "c")
+	if err != nil || fmt.Sprint(got) != "[map[v:a] map[v:b]]" {
+		t.Fatalf("message limit: got %v, %v", got, err)
+	}
+	got, err = collect(StreamLimits{MaxDuration: 10 * time.Millisecond}, "a")
+	if err != nil || fmt.Sprint(got) != "[map[v:a]]" {
+		t.Fatalf("duration limit: got %v, %v", got, err)
+	}
+
+	var sink recordingSink
+	ctx := withMessageSink(context.Background(), &sink)
+	open := func(ctx context.Context) (StreamReceiver[*structpb.Struct], error) {
+		return &fakeStream{ctx: ctx, values: []string{"a", "EOF"}}, nil
+	}
+	if _, err := CollectStream(ctx, StreamLimits{}, open); err != nil || len(sink.msgs) != 0 {
+		t.Fatalf("messages logged at the default level: %v, %v", sink.msgs, err)
+	}
+	ctx = context.WithValue(ctx, defaultLogLevelKey{}, LogLevelDebug)
+	if _, err := CollectStream(ctx, StreamLimits{}, open); err != nil || len(sink.msgs) != 1 {
+		t.Fatalf("messages not logged at debug: %v, %v", sink.msgs, err)
+	}
+}
+
+func TestStreamItems(t *testing.T) {
+	items, err := StreamItems(map[string]any{"items": []any{map[string]any{"a": 1}, map[string]any{}}}, 2)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("got %v, %v", items, err)
+	}
+	for _, args := range []map[string]any{
+		{"items": "x"},
+		{"items": []any{1}},
+		{"items": []any{map[string]any{}, map[string]any{}, map[string]any{}}},
+	} {
+		if _, err := StreamItems(args, 2); err == nil {
+			t.Errorf("StreamItems(%v): expected error", args)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithDefaultToolTimeout bounds tool calls whose ToolHandler sets no Timeout.
+func WithDefaultToolTimeout(d time.Duration) Option {
+	return func(mux *MCPServeMux) {
+		if d > 0 {
+			mux.defaultToolTimeout = d
+		}
+	}
+}
+
+// WithMaxClientTimeout caps the timeout a client may request for a tool call
+// through _meta.timeout. It matters for tools that have no timeout of their
+// own and no mux default; otherwise clients can only shorten those anyway.
+func WithMaxClientTimeout(d time.Duration) Option {
+	return func(mux *MCPServeMux) {
+		if d > 0 {
+			mux.maxClientTimeout = d
+		}
+	}
+}
+
+// toolTimeout returns the timeout of a tool call: the tool's own, else the
+// mux default, shortened to the one requested in _meta.timeout. A client can
+// never lengthen a call, and its timeout is capped by WithMaxClientTimeout.
+// Zero means no timeout.
+func (mux *MCPServeMux) toolTimeout(tool *ToolHandler, params map[string]interface{}) (time.Duration, *MCPError) {
+	timeout := tool.Timeout
+	if timeout <= 0 {
+		timeout = mux.defaultToolTimeout
+	}
+	meta, _ := params["_meta"].(map[string]interface{})
+	raw, ok := meta["timeout"]
+	if !ok || raw == nil {
+		return timeout, nil
+	}
+	d, ok := parseTimeout(raw)
+	if !ok {
+		return 0, &MCPError{Code: -32602, Message: fmt.Sprintf("Invalid _meta.timeout: %v", raw)}
+	}
+	if timeout > 0 && d > timeout {
+		d = timeout
+	}
+	if mux.maxClientTimeout > 0 && d > mux.maxClientTimeout {
+		d = mux.maxClientTimeout
+	}
+	return d, nil
+}
+
+// parseTimeout accepts a duration string such as "1.5s" or a number of
+// seconds.
+func parseTimeout(raw any) (time.Duration, bool) {
+	var d time.Duration
+	switch v := raw.(type) {
+	case string:
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			return 0, false
+		}
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	default:
+		return 0, false
+	}
+	return d, d > 0
+}
+
+// timeoutError reports a tool call that ran out of time.
+func timeoutError(name string, timeout time.Duration) error {
+	return status.Errorf(codes.DeadlineExceeded, "tool %s did not complete within %s", name, timeout)
+}
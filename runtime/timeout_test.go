@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+func TestToolTimeout(t *testing.T) {
+	var deadlines []time.Duration
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"}, WithMaxClientTimeout(time.Minute))
+	mux.RegisterTool(&ToolHandler{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			deadline, _ := ctx.Deadline()
+			deadlines = append(deadlines, time.Until(deadline).Round(time.Minute))
+			if args["block"] == true {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+			return "ok", nil
+		},
+	})
+
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "slow", "arguments": map[string]any{"block": true}}), latestVersion)
+	want := `"text":"DEADLINE_EXCEEDED: tool slow did not complete within 10ms (retryable)"`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("got %s, want %s", rec.Body.String(), want)
+	}
+
+	postJSON(t, mux, rpc(2, "tools/call", map[string]any{"name": "slow", "_meta": map[string]any{"timeout": "1h"}}), latestVersion)
+	if deadlines[1] != 0 {
+		t.Errorf("client timeout: got %s, want the tool's 10ms", deadlines[1])
+	}
+
+	mux.RegisterTool(&ToolHandler{Name: "open", Handler: mux.tools["slow"].Handler})
+	postJSON(t, mux, rpc(4, "tools/call", map[string]any{"name": "open", "_meta": map[string]any{"timeout": "1h"}}), latestVersion)
+	if deadlines[2] != time.Minute {
+		t.Errorf("client timeout without a tool timeout: got %s, want capped at 1m", deadlines[2])
+	}
+
+	rec = postJSON(t, mux, rpc(3, "tools/call", map[string]any{"name": "slow", "_meta": map[string]any{"timeout": "soon"}}), latestVersion)
+	var resp MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("invalid timeout: got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ArgTransformer rewrites tool arguments before they are validated and
+// decoded. name is the tool being called, or the resource name for resource
+// reads. Returning an error fails the call with that error.
+type ArgTransformer func(ctx context.Context, name string, args map[string]any) (map[string]any, error)
+
+// ArgPreprocessor is a function that preprocesses arguments before decoding.
+// This can be used to transform enum values or perform other custom conversions.
+//
+// Deprecated: Use ArgTransformer with WithArgTransformers or
+// ToolHandler.ArgTransformers.
+type ArgPreprocessor func(args map[string]any) map[string]any
+
+var globalPreprocessor atomic.Pointer[ArgPreprocessor]
+
+// SetArgPreprocessor sets a process-wide argument preprocessor. It runs
+// first in every mux's transformer chain, and in DecodeArgs and
+// DecodeArgsContext when they are called outside a mux call. Passing nil
+// removes it.
+//
+// Deprecated: Use WithArgTransformers or ToolHandler.ArgTransformers, which
+// are scoped to one mux or tool.
+func SetArgPreprocessor(fn ArgPreprocessor) {
+	if fn == nil {
+		globalPreprocessor.Store(nil)
+		return
+	}
+	globalPreprocessor.Store(&fn)
+}
+
+// WithArgTransformers appends transformers that run, in order, on the
+// arguments of every tool call and resource read, before any transformers
+// set on the tool itself.
+func WithArgTransformers(fns ...ArgTransformer) Option {
+	return func(mux *MCPServeMux) {
+		for _, fn := range fns {
+			if fn != nil {
+				mux.argTransformers = append(mux.argTransformers, fn)
+			}
+		}
+	}
+}
+
+// preprocessArgs applies the global preprocessor, if any, to args.
+func preprocessArgs(args map[string]any) map[string]any {
+	pre := globalPreprocessor.Load()
+	if pre == nil {
+		return args
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	return (*pre)(args)
+}
+
+// transformArgs runs the global preprocessor, the mux chain and then the
+// tool chain over args.
+func (mux *MCPServeMux) transformArgs(ctx context.Context, name string, args map[string]any, tool []ArgTransformer) (map[string]any, error) {
+	pre := globalPreprocessor.Load()
+	if pre == nil && len(mux.argTransformers) == 0 && len(tool) == 0 {
+		return args, nil
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	if pre != nil {
+		args = (*pre)(args)
+	}
+	for _, chain := range [][]ArgTransformer{mux.argTransformers, tool} {
+		for _, fn := range chain {
+			var err error
+			if args, err = fn(ctx, name, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return args, nil
+}
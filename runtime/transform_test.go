@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestArgTransformers(t *testing.T) {
+	appendStep := func(step string) ArgTransformer {
+		return func(ctx context.Context, name string, args map[string]any) (map[string]any, error) {
+			steps, _ := args["steps"].([]string)
+			args["steps"] = append(steps, name+":"+step)
+			return args, nil
+		}
+	}
+	newMux := func(opts ...Option) *MCPServeMux {
+		mux := NewMCPServeMux(ServerMetadata{Name: "test"}, opts...)
+		mux.RegisterTool(&ToolHandler{
+			Name:            "echo",
+			ArgTransformers: []ArgTransformer{appendStep("tool")},
+			Handler: func(ctx context.Context, args map[string]any) (any, error) {
+				return strings.Join(args["steps"].([]string), ","), nil
+			},
+		})
+		mux.RegisterTool(&ToolHandler{
+			Name: "fail",
+			Handler: func(ctx context.Context, args map[string]any) (any, error) {
+				t.Error("handler called after transformer error")
+				return nil, nil
+			},
+		})
+		return mux
+	}
+	reject := func(ctx context.Context, name string, args map[string]any) (map[string]any, error) {
+		if name == "fail" {
+			return nil, status.Error(codes.InvalidArgument, "rejected")
+		}
+		return args, nil
+	}
+
+	callText := func(mux *MCPServeMux, name string) (string, bool) {
+		t.Helper()
+		rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": name}), latestVersion)
+		var resp struct {
+			Result struct {
+				IsError bool `json:"isError"`
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Result.Content) == 0 {
+			t.Fatalf("decode response %s: %v", rec.Body.String(), err)
+		}
+		return resp.Result.Content[0].Text, resp.Result.IsError
+	}
+
+	a := newMux(WithArgTransformers(appendStep("a1"), appendStep("a2"), reject))
+	b := newMux(WithArgTransformers(appendStep("b")))
+	if got, _ := callText(a, "echo"); got != "echo:a1,echo:a2,echo:tool" {
+		t.Errorf("mux a: got %q", got)
+	}
+	if got, _ := callText(b, "echo"); got != "echo:b,echo:tool" {
+		t.Errorf("mux b: got %q", got)
+	}
+	if got, isError := callText(a, "fail"); !isError || got != "INVALID_ARGUMENT: rejected" {
+		t.Errorf("transformer error: got %q (isError=%v)", got, isError)
+	}
+
+	SetArgPreprocessor(func(args map[string]any) map[string]any {
+		args["steps"] = []string{"global"}
+		return args
+	})
+	t.Cleanup(func() { SetArgPreprocessor(nil) })
+	if got, _ := callText(b, "echo"); got != "global,echo:b,echo:tool" {
+		t.Errorf("global preprocessor: got %q", got)
+	}
+
+	SetArgPreprocessor(func(args map[string]any) map[string]any {
+		args["name"] = "preprocessed"
+		return args
+	})
+	for name, decode := range map[string]func(map[string]any, proto.Message) error{
+		"DecodeArgs": DecodeArgs,
+		"DecodeArgsContext": func(args map[string]any, msg proto.Message) error {
+			return DecodeArgsContext(context.Background(), args, msg)
+		},
+	} {
+		msg := &typepb.Field{}
+		if err := decode(map[string]any{}, msg); err != nil || msg.GetName() != "preprocessed" {
+			t.Errorf("%s outside a call: got %q, %v", name, msg.GetName(), err)
+		}
+	}
+}
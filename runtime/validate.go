@@ -0,0 +1,522 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ArgumentViolation describes one problem with the arguments of a tool call.
+type ArgumentViolation struct {
+	// Pointer is the JSON pointer of the offending value within the
+	// arguments, e.g. "/items/0/name". The empty pointer is the arguments
+	// object itself.
+	Pointer string `json:"pointer"`
+	// Path is the same location as a field path, e.g. "items[0].name".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+	// Expected and Got describe a value of the wrong type or form, e.g.
+	// "integer" and `string "ten"`. Expected is always a JSON Schema type,
+	// whether validation or decoding found the problem.
+	Expected string `json:"expected,omitempty"`
+	Got      string `json:"got,omitempty"`
+	// AllowedValues lists the values the field accepts, for enums.
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
+
+// ArgumentError reports invalid tool arguments. The tool error result lists
+// every violation.
+type ArgumentError struct {
+	Violations []ArgumentViolation
+}
+
+func (e *ArgumentError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = violationLabel(v.Pointer) + ": " + v.Message
+	}
+	return "invalid arguments: " + strings.Join(msgs, "; ")
+}
+
+func violationLabel(pointer string) string {
+	if pointer == "" {
+		return "arguments"
+	}
+	return pointer
+}
+
+// ValidateArgs checks tool arguments against a JSON Schema and returns every
+// violation. It supports the keywords the generator emits (type, properties,
+// required, additionalProperties, items, enum, maxItems) as well as
+// minItems, minimum, maximum, minLength, maxLength, pattern and const.
+//
+// Values are checked the way protojson decodes them: numeric strings are
+// accepted for integers and numbers, and a null property counts as absent.
+// Properties are matched by the names in the schema only; the mux also
+// accepts proto field names for tools that set ToolHandler.InputDescriptor.
+func ValidateArgs(schema map[string]any, args map[string]any) []ArgumentViolation {
+	if schema == nil {
+		return nil
+	}
+	var v validator
+	v.validate(schema, args, "", nil)
+	return v.violations
+}
+
+// validateToolArgs is ValidateArgs for the arguments of tool, optionally
+// letting unknown fields through for tools that decode with
+// JSONOptions.DiscardUnknown. When tool has an InputDescriptor, arguments are
+// matched to properties under either the proto or the JSON field name, as
+// protojson does; for client-streaming tools it describes each of the
+// "items".
+func validateToolArgs(tool *ToolHandler, args map[string]any, discardUnknown bool) []ArgumentViolation {
+	if tool.InputSchema == nil {
+		return nil
+	}
+	v := validator{discardUnknown: discardUnknown}
+	var top protoreflect.Descriptor
+	if tool.ClientStreaming {
+		v.itemsDesc = tool.InputDescriptor
+	} else if tool.InputDescriptor != nil {
+		top = tool.InputDescriptor
+	}
+	v.validate(tool.InputSchema, args, "", top)
+	return v.violations
+}
+
+type validator struct {
+	violations     []ArgumentViolation
+	discardUnknown bool
+	// itemsDesc describes the "items" of a client-streaming tool call.
+	itemsDesc protoreflect.MessageDescriptor
+}
+
+func (v *validator) add(pointer, format string, args ...any) {
+	v.violations = append(v.violations, ArgumentViolation{
+		Pointer: pointer,
+		Path:    fieldPath(pointer),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks value against schema. desc describes value when it is a
+// message or a list of messages (a MessageDescriptor) or a map field (a
+// FieldDescriptor); it is nil when value is not backed by a message.
+func (v *validator) validate(schema map[string]any, value any, pointer string, desc protoreflect.Descriptor) {
+	if types := schemaStrings(schema["type"]); len(types) > 0 && !matchesAnyType(types, value) {
+		v.add(pointer, "must be %s", describeTypes(types))
+		last := &v.violations[len(v.violations)-1]
+		last.Expected, last.Got = strings.Join(types, " or "), describeJSON(value)
+		return
+	}
+	if enum, ok := schema["enum"]; ok {
+		values := schemaValues(enum)
+		if !containsValue(values, value) {
+			v.add(pointer, "must be one of %s", describeValues(values))
+			last := &v.violations[len(v.violations)-1]
+			last.Expected, last.Got = strings.Join(schemaStrings(schema["type"]), " or "), describeJSON(value)
+			for _, allowed := range values {
+				last.AllowedValues = append(last.AllowedValues, fmt.Sprint(allowed))
+			}
+		}
+	}
+	if c, ok := schema["const"]; ok && !equalValues(c, value) {
+		v.add(pointer, "must be %s", describeValues([]any{c}))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(schema, val, pointer, desc)
+	case []any:
+		if n, ok := schemaNumber(schema["minItems"]); ok && float64(len(val)) < n {
+			v.add(pointer, "must have at least %v items", n)
+		}
+		if n, ok := schemaNumber(schema["maxItems"]); ok && float64(len(val)) > n {
+			v.add(pointer, "must have at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				v.validate(items, item, pointer+"/"+strconv.Itoa(i), desc)
+			}
+		}
+	case string:
+		if n, ok := schemaNumber(schema["minLength"]); ok && float64(len([]rune(val))) < n {
+			v.add(pointer, "must be at least %v characters long", n)
+		}
+		if n, ok := schemaNumber(schema["maxLength"]); ok && float64(len([]rune(val))) > n {
+			v.add(pointer, "must be at most %v characters long", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(val) {
+				v.add(pointer, "must match pattern %s", pattern)
+			}
+		}
+		if f, ok := numericString(val); ok {
+			v.validateRange(schema, f, pointer)
+		}
+	case float64:
+		v.validateRange(schema, val, pointer)
+	}
+}
+
+func (v *validator) validateObject(schema map[string]any, obj map[string]any, pointer string, desc protoreflect.Descriptor) {
+	properties, _ := schema["properties"].(map[string]any)
+	msg, _ := desc.(protoreflect.MessageDescriptor)
+	for _, name := range schemaStrings(schema["required"]) {
+		if obj[name] != nil {
+			continue
+		}
+		if field := messageField(msg, name); field != nil && (obj[field.JSONName()] != nil || obj[string(field.Name())] != nil) {
+			continue
+		}
+		v.add(pointer+"/"+escapePointer(name), "is required")
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := obj[k]
+		child := pointer + "/" + escapePointer(k)
+		field := messageField(msg, k)
+		propSchema, ok := properties[k].(map[string]any)
+		if !ok && field != nil {
+			// The schema names the field one way, the client the other.
+			if propSchema, ok = properties[field.JSONName()].(map[string]any); !ok {
+				propSchema, ok = properties[string(field.Name())].(map[string]any)
+			}
+		}
+		if ok {
+			valueDesc := fieldValueDescriptor(field)
+			if pointer == "" && k == "items" && v.itemsDesc != nil {
+				valueDesc = v.itemsDesc
+			}
+			if value != nil {
+				v.validate(propSchema, value, child, valueDesc)
+			}
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra && !v.discardUnknown {
+				v.add(child, "is not a known field")
+			}
+		case map[string]any:
+			if value != nil {
+				var valueDesc protoreflect.Descriptor
+				if mapField, ok := desc.(protoreflect.FieldDescriptor); ok && mapField.IsMap() {
+					valueDesc = fieldValueDescriptor(mapField.MapValue())
+				}
+				v.validate(extra, value, child, valueDesc)
+			}
+		}
+	}
+}
+
+// messageField returns the field of msg with the given JSON or proto name.
+// Well-known types are encoded specially and have no named fields in JSON.
+func messageField(msg protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if msg == nil || isWellKnownMessage(msg) {
+		return nil
+	}
+	if field := msg.Fields().ByJSONName(name); field != nil {
+		return field
+	}
+	return msg.Fields().ByName(protoreflect.Name(name))
+}
+
+// fieldValueDescriptor returns the descriptor validate expects for the value
+// of field: the field itself for maps, its message type for message fields
+// and lists of messages, and nil otherwise.
+func fieldValueDescriptor(field protoreflect.FieldDescriptor) protoreflect.Descriptor {
+	switch {
+	case field == nil:
+		return nil
+	case field.IsMap():
+		return field
+	case field.Message() != nil:
+		return field.Message()
+	}
+	return nil
+}
+
+func (v *validator) validateRange(schema map[string]any, f float64, pointer string) {
+	if n, ok := schemaNumber(schema["minimum"]); ok && f < n {
+		v.add(pointer, "must be at least %v", n)
+	}
+	if n, ok := schemaNumber(schema["maximum"]); ok && f > n {
+		v.add(pointer, "must be at most %v", n)
+	}
+}
+
+func matchesAnyType(types []string, value any) bool {
+	for _, t := range types {
+		if matchesType(t, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "integer":
+		f, ok := value.(float64)
+		if s, isString := value.(string); isString {
+			f, ok = numericString(s)
+		}
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	case "number":
+		switch val := value.(type) {
+		case float64:
+			return true
+		case string:
+			if val == "NaN" || val == "Infinity" || val == "-Infinity" {
+				return true
+			}
+			_, ok := numericString(val)
+			return ok
+		}
+	}
+	return false
+}
+
+func numericString(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// kindType returns the JSON Schema type of a field kind, as the generator
+// writes it into input schemas. Validation and decode errors both report it
+// as Expected.
+func kindType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.EnumKind:
+		return "string"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "number"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "object"
+	}
+	return "integer"
+}
+
+// matchesKind reports whether protojson accepts value for a scalar field of
+// kind: value must match kindType(kind), integers must fit the kind and bytes
+// must be base64.
+func matchesKind(value any, kind protoreflect.Kind) bool {
+	if !matchesType(kindType(kind), value) {
+		return false
+	}
+	switch kind {
+	case protoreflect.BytesKind:
+		return validBase64(value.(string))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return validInteger(value, math.MinInt32, math.MaxInt32)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return validInteger(value, 0, math.MaxUint32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if s, ok := value.(string); ok {
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return true
+			}
+		}
+		return validInteger(value, math.MinInt64, math.MaxInt64)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if s, ok := value.(string); ok {
+			if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return true
+			}
+		}
+		return validInteger(value, 0, math.MaxUint64)
+	}
+	return true
+}
+
+// validInteger reports whether value is a number, or numeric string, holding
+// an integer within [lo, hi].
+func validInteger(value any, lo, hi float64) bool {
+	f, ok := value.(float64)
+	if s, isString := value.(string); isString {
+		f, ok = numericString(s)
+	}
+	return ok && f == math.Trunc(f) && f >= lo && f <= hi
+}
+
+func validBase64(s string) bool {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if _, err := enc.DecodeString(s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func describeTypes(types []string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "object", "array", "integer":
+			names[i] = "an " + t
+		case "null":
+			names[i] = "null"
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+func describeValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			parts[i] = strconv.Quote(s)
+		} else {
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if equalValues(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalValues(a, b any) bool {
+	if fa, ok := schemaNumber(a); ok {
+		fb, ok := schemaNumber(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// schemaStrings reads a keyword holding a string or a list of strings.
+func schemaStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []string:
+		return val
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaValues reads a keyword holding a list of values.
+func schemaValues(v any) []any {
+	switch val := v.(type) {
+	case []any:
+		return val
+	case []string:
+		out := make([]any, len(val))
+		for i, s := range val {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaNumber reads a numeric keyword written as a Go literal or decoded
+// from JSON.
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// describeJSON names the JSON type of a decoded value, with the value itself
+// for scalars, e.g. `string "ten"`.
+func describeJSON(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprintf("boolean %t", val)
+	case float64:
+		return "number " + strconv.FormatFloat(val, 'g', -1, 64)
+	case string:
+		if r := []rune(val); len(r) > 40 {
+			val = string(r[:40]) + "..."
+		}
+		return "string " + strconv.Quote(val)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// fieldPath turns a JSON pointer into a field path: "/items/0/name" becomes
+// "items[0].name".
+func fieldPath(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, segment := range strings.Split(pointer[1:], "/") {
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		if _, err := strconv.Atoi(segment); err == nil {
+			b.WriteString("[" + segment + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,167 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestArgumentValidation(t *testing.T) {
+	schema := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"title"},
+		"properties": map[string]any{
+			"title":    map[string]any{"type": "string"},
+			"priority": map[string]any{"type": "string", "enum": []string{"LOW", "HIGH"}},
+			"count":    map[string]any{"type": "integer", "format": "int64"},
+			"tags": map[string]any{
+				"type":     "array",
+				"maxItems": 2,
+				"items":    map[string]any{"type": "string"},
+			},
+		},
+	}
+	calls := 0
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	for _, name := range []string{"strict", "lenient"} {
+		mux.RegisterTool(&ToolHandler{
+			Name:           name,
+			InputSchema:    schema,
+			SkipValidation: name == "lenient",
+			Handler: func(ctx context.Context, args map[string]any) (any, error) {
+				calls++
+				return "ok", nil
+			},
+		})
+	}
+
+	bad := map[string]any{
+		"priority": "URGENT",
+		"count":    "12",
+		"tags":     []any{"a", 2, "c"},
+		"owner":    "me",
+	}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "strict", "arguments": bad}), latestVersion)
+	var resp struct {
+		Result struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := strings.Join([]string{
+		"INVALID_ARGUMENT: invalid arguments",
+		"- /title: is required",
+		"- /owner: is not a known field",
+		`- /priority: must be one of "LOW", "HIGH"`,
+		"- /tags: must have at most 2 items",
+		"- /tags/1: must be a string",
+	}, "\n")
+	if !resp.Result.IsError || resp.Result.Content[0].Text != want {
+		t.Fatalf("got %s, want:\n%s", rec.Body.String(), want)
+	}
+	if calls != 0 {
+		t.Fatal("handler called with invalid arguments")
+	}
+
+	postJSON(t, mux, rpc(2, "tools/call", map[string]any{"name": "lenient", "arguments": bad}), latestVersion)
+	postJSON(t, mux, rpc(3, "tools/call", map[string]any{"name": "strict", "arguments": map[string]any{"title": "t", "count": 3, "priority": nil}}), latestVersion)
+	if calls != 2 {
+		t.Fatalf("got %d handler calls, want 2", calls)
+	}
+}
+
+func TestArgumentValidationProtoNames(t *testing.T) {
+	object := func(required []string, properties map[string]any) map[string]any {
+		return map[string]any{"type": "object", "additionalProperties": false, "required": required, "properties": properties}
+	}
+	schema := object([]string{"name"}, map[string]any{
+		"name": map[string]any{"type": "string"},
+		"nestedType": map[string]any{"type": "array", "items": object(nil, map[string]any{
+			"name": map[string]any{"type": "string"},
+			"field": map[string]any{"type": "array", "items": object([]string{"jsonName"}, map[string]any{
+				"jsonName": map[string]any{"type": "string"},
+			})},
+		})},
+	})
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	mux.RegisterTool(&ToolHandler{
+		Name:            "define",
+		InputSchema:     schema,
+		InputDescriptor: (&descriptorpb.DescriptorProto{}).ProtoReflect().Descriptor(),
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			msg := &descriptorpb.DescriptorProto{}
+			if err := DecodeArgs(args, msg); err != nil {
+				return nil, err
+			}
+			return msg.GetNestedType()[0].GetField()[0].GetJsonName(), nil
+		},
+	})
+
+	args := map[string]any{
+		"name":        "M",
+		"nested_type": []any{map[string]any{"name": "N", "field": []any{map[string]any{"json_name": "j"}}}},
+	}
+	rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": "define", "arguments": args}), latestVersion)
+	if !strings.Contains(rec.Body.String(), `"isError":false`) || !strings.Contains(rec.Body.String(), `"text":"j"`) {
+		t.Fatalf("proto field names rejected: %s", rec.Body.String())
+	}
+
+	args = map[string]any{"nested_type": []any{map[string]any{"field": []any{map[string]any{}}}}}
+	rec = postJSON(t, mux, rpc(2, "tools/call", map[string]any{"name": "define", "arguments": args}), latestVersion)
+	for _, want := range []string{"/name: is required", "/nested_type/0/field/0/jsonName: is required"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Fatalf("missing %q in %s", want, rec.Body.String())
+		}
+	}
+}
+
+func TestArgumentValidationClientStreaming(t *testing.T) {
+	object := func(properties map[string]any) map[string]any {
+		return map[string]any{"type": "object", "additionalProperties": false, "properties": properties}
+	}
+	mux := NewMCPServeMux(ServerMetadata{Name: "test"})
+	handler := func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil }
+	mux.RegisterTool(&ToolHandler{
+		Name: "import",
+		InputSchema: object(map[string]any{
+			"items": map[string]any{"type": "array", "maxItems": 1000, "items": object(map[string]any{
+				"jsonName": map[string]any{"type": "string"},
+			})},
+		}),
+		InputDescriptor: (&descriptorpb.FieldDescriptorProto{}).ProtoReflect().Descriptor(),
+		ClientStreaming: true,
+		Handler:         handler,
+	})
+	mux.RegisterTool(&ToolHandler{
+		Name:            "define",
+		InputSchema:     object(map[string]any{"name": map[string]any{"type": "string"}, "nestedType": map[string]any{"type": "array"}}),
+		InputDescriptor: (&descriptorpb.DescriptorProto{}).ProtoReflect().Descriptor(),
+		Handler:         handler,
+	})
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want string
+	}{
+		{"import", map[string]any{"items": []any{map[string]any{"json_name": "j"}}}, `"isError":false`},
+		{"import", map[string]any{"items": []any{map[string]any{"json_name": 3}}}, "/items/0/json_name: must be a string"},
+		{"import", map[string]any{"items": "x"}, "/items: must be an array"},
+		// A stray "items" list does not make a unary tool's arguments a stream.
+		{"define", map[string]any{"nested_type": []any{}, "items": []any{}}, `invalid arguments\n- /items: is not a known field"`},
+	} {
+		rec := postJSON(t, mux, rpc(1, "tools/call", map[string]any{"name": tc.tool, "arguments": tc.args}), latestVersion)
+		if !strings.Contains(rec.Body.String(), tc.want) {
+			t.Errorf("%s %v: missing %q in %s", tc.tool, tc.args, tc.want, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ProtocolVersionHeader is the HTTP header carrying the negotiated MCP
+// protocol version on requests after initialize.
+const ProtocolVersionHeader = "Mcp-Protocol-Version"
+
+// LatestProtocolVersion is the newest MCP revision the mux implements.
+const LatestProtocolVersion = "2025-11-25"
+
+// FallbackProtocolVersion is the version assumed for requests that carry no
+// Mcp-Protocol-Version header and belong to no session that negotiated one,
+// as the specification requires.
+const FallbackProtocolVersion = "2025-03-26"
+
+// supportedProtocolVersions lists the MCP revisions the mux can speak, newest
+// first. Revision strings are dates, so they order lexically.
+var supportedProtocolVersions = []string{
+	LatestProtocolVersion,
+	"2025-06-18",
+	"2025-03-26",
+	"2024-11-05",
+}
+
+type protocolVersionKey struct{}
+
+// negotiateProtocolVersion picks the version to answer initialize with. The
+// client's version is echoed when supported; otherwise the newest supported
+// version that is not newer than the client's is used, falling back to the
+// latest one so the client can decide whether to disconnect.
+func negotiateProtocolVersion(requested string) string {
+	for _, v := range supportedProtocolVersions {
+		if v <= requested {
+			return v
+		}
+	}
+	return LatestProtocolVersion
+}
+
+func isSupportedProtocolVersion(version string) bool {
+	for _, v := range supportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func withProtocolVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, protocolVersionKey{}, version)
+}
+
+// ProtocolVersionFromContext returns the MCP protocol version in effect for
+// the request: the Mcp-Protocol-Version header, else the version negotiated
+// for the session, else FallbackProtocolVersion.
+func ProtocolVersionFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(protocolVersionKey{}).(string); ok && v != "" {
+		return v
+	}
+	if sess := sessionFromContext(ctx); sess != nil {
+		if v := sess.negotiatedVersion(); v != "" {
+			return v
+		}
+	}
+	return FallbackProtocolVersion
+}
+
+// protocolVersionFromRequest validates the Mcp-Protocol-Version header and
+// attaches it to ctx. It writes a 400 response and returns false when the
+// header names a version the mux does not support, or one other than the
+// version negotiated for the session in ctx.
+func protocolVersionFromRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	version := r.Header.Get(ProtocolVersionHeader)
+	if version == "" {
+		return ctx, true
+	}
+	if !isSupportedProtocolVersion(version) {
+		sendErrorStatus(w, nil, -32600, "Unsupported protocol version: "+version, http.StatusBadRequest)
+		return ctx, false
+	}
+	if sess := sessionFromContext(ctx); sess != nil {
+		if negotiated := sess.negotiatedVersion(); negotiated != "" && negotiated != version {
+			sendErrorStatus(w, nil, -32600, fmt.Sprintf("Protocol version %s does not match the version negotiated for the session, %s", version, negotiated), http.StatusBadRequest)
+			return ctx, false
+		}
+	}
+	return withProtocolVersion(ctx, version), true
+}
+
+// supportsToolAnnotations reports whether version carries tool annotations.
+func supportsToolAnnotations(version string) bool {
+	return version >= "2025-03-26"
+}
+
+// supportsTitles reports whether version carries display titles on tools,
+// resources and prompts.
+func supportsTitles(version string) bool {
+	return version >= "2025-06-18"
+}
+
+// supportsStructuredContent reports whether version carries structuredContent
+// and outputSchema.
+func supportsStructuredContent(version string) bool {
+	return version >= "2025-06-18"
+}
@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProtocolVersionNegotiation(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      string
+	}{
+		{"2025-11-25", "2025-11-25"},
+		{"2025-06-18", "2025-06-18"},
+		{"2024-11-05", "2024-11-05"},
+		{"2025-05-01", "2025-03-26"},
+		{"2099-01-01", LatestProtocolVersion},
+		{"", LatestProtocolVersion},
+	}
+	for _, tt := range tests {
+		if got := negotiateProtocolVersion(tt.requested); got != tt.want {
+			t.Errorf("negotiateProtocolVersion(%q) = %q, want %q", tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestProtocolVersionGating(t *testing.T) {
+	mux := newTestMux(WithSessions())
+	rec := postJSON(t, mux, rpc(1, "initialize", map[string]any{"protocolVersion": "2025-03-26"}), nil)
+	id := rec.Header().Get(SessionIDHeader)
+
+	call := rpc(2, "tools/call", map[string]any{"name": "echo", "arguments": map[string]any{"a": 1}})
+	rec = postJSON(t, mux, call, http.Header{SessionIDHeader: {id}})
+	if strings.Contains(rec.Body.String(), "structuredContent") {
+		t.Fatalf("structuredContent sent to 2025-03-26 client: %s", rec.Body.String())
+	}
+
+	rec = postJSON(t, mux, call, http.Header{SessionIDHeader: {id}, ProtocolVersionHeader: {"1999-01-01"}})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unsupported header version: got status %d, want 400", rec.Code)
+	}
+
+	rec = postJSON(t, mux, call, http.Header{SessionIDHeader: {id}, ProtocolVersionHeader: {"2025-06-18"}})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("header conflicting with session version: got status %d, want 400", rec.Code)
+	}
+
+	rec = postJSON(t, newTestMux(), call, nil)
+	if strings.Contains(rec.Body.String(), "structuredContent") {
+		t.Fatalf("structuredContent sent to stateless client without a version header: %s", rec.Body.String())
+	}
+}